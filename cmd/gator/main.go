@@ -25,9 +25,17 @@ func main() {
 
 	dbQueries := database.New(db)
 
+	fetcher, err := config.NewFetcher(cfg.Fetch)
+	if err != nil {
+		fmt.Printf("Error configuring HTTP client: %v\n", err)
+		return
+	}
+
 	state := config.State{
-		Config: cfg,
-		DB:     dbQueries,
+		Config:  cfg,
+		DB:      dbQueries,
+		Conn:    db,
+		Fetcher: fetcher,
 	}
 
 	commands := config.NewCommands()
@@ -3,6 +3,10 @@ module github.com/JonahLargen/BlogAggregator
 go 1.24.4
 
 require (
+	github.com/andybalholm/brotli v1.2.6
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.44.0
 )
+
+require golang.org/x/text v0.29.0 // indirect
@@ -2,14 +2,18 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"math"
+	"net/http"
+	"net/url"
 	"time"
 
 	"database/sql"
 
 	"github.com/JonahLargen/BlogAggregator/internal/database"
 	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/JonahLargen/BlogAggregator/internal/sanitize"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
@@ -26,11 +30,69 @@ func agg(s *State, time_between_reqs string) error {
 		if err != nil {
 			return fmt.Errorf("error scraping feeds: %w", err)
 		}
+		if s.Config.Retention.AutoPrune {
+			pruned, err := prunePosts(s, false)
+			if err != nil {
+				return fmt.Errorf("error pruning posts: %w", err)
+			}
+			if pruned > 0 {
+				fmt.Printf("Pruned %d posts\n", pruned)
+			}
+		}
+		removed, err := collectOrphanedFeeds(s, false)
+		if err != nil {
+			return fmt.Errorf("error removing feeds without followers: %w", err)
+		}
+		if removed > 0 {
+			fmt.Printf("Removed %d feeds without followers\n", removed)
+		}
+		if _, err := sweepImageCache(s); err != nil {
+			return fmt.Errorf("error cleaning image cache: %w", err)
+		}
+	}
+}
+
+// Bounds on how long a throttled feed is left alone.
+const (
+	defaultRetryAfter = 10 * time.Minute
+	maxRetryAfter     = 24 * time.Hour
+)
+
+// deferFeed postpones the next fetch of a feed whose server answered 429 or
+// 503, honoring its Retry-After header within sane bounds.
+func deferFeed(s *State, feed database.Feed, statusErr *rss.StatusError) error {
+	delay := statusErr.RetryAfter
+	if delay <= 0 {
+		delay = defaultRetryAfter
+	}
+	delay = min(delay, maxRetryAfter)
+	next := time.Now().Add(delay)
+	err := s.DB.SetFeedNextFetchAt(context.Background(), database.SetFeedNextFetchAtParams{
+		NextFetchAt: sql.NullTime{Time: next, Valid: true},
+		ID:          feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error deferring feed %s: %w", feed.Url, err)
 	}
+	fmt.Printf("Feed %s answered %s, retrying after %s\n", feed.Url, statusErr.Status, next.Format(time.RFC1123))
+	return nil
+}
+
+// positiveInt32 stores n when it is positive and fits an INTEGER column, and
+// NULL otherwise, so absurd feed values are dropped rather than wrapped.
+func positiveInt32(n int64) sql.NullInt32 {
+	if n <= 0 || n > math.MaxInt32 {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(n), Valid: true}
 }
 
 func scrapeFeeds(s *State) error {
-	nextFeed, err := s.DB.GetNextFeedToFetch(context.Background())
+	nextFeed, err := s.DB.GetNextFeedToFetch(context.Background(), time.Now())
+	if err == sql.ErrNoRows {
+		fmt.Println("No feeds are due for fetching")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error fetching next feed: %w", err)
 	}
@@ -42,25 +104,91 @@ func scrapeFeeds(s *State) error {
 	if err != nil {
 		return fmt.Errorf("error marking feed as fetched: %w", err)
 	}
-	feed, err := rss.FetchFeed(context.Background(), nextFeed.Url)
+	creds, err := loadCredentials(s, nextFeed.ID)
+	if err != nil {
+		return fmt.Errorf("error loading credentials for feed %s: %w", nextFeed.Url, err)
+	}
+	started := time.Now()
+	feed, err := s.Fetcher.FetchFeed(context.Background(), nextFeed.Url, creds)
+	if recordErr := recordFetch(s, nextFeed.ID, started, feed, err); recordErr != nil {
+		return recordErr
+	}
+	var statusErr *rss.StatusError
+	if errors.As(err, &statusErr) && statusErr.Throttled() {
+		return deferFeed(s, nextFeed, statusErr)
+	}
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusGone {
+		return retireFeed(s, nextFeed, fmt.Sprintf("server answered %s", statusErr.Status))
+	}
 	if err != nil {
 		return fmt.Errorf("error scraping feed %s: %w", nextFeed.Url, err)
 	}
 	fmt.Println("Feed scraped successfully")
+	if feed.Recovered {
+		fmt.Printf("Warning: feed %s is malformed and was parsed in tolerant mode\n", nextFeed.Url)
+	}
+	if feed.MovedTo != "" && feed.MovedTo != nextFeed.Url {
+		if err := moveFeed(s, nextFeed, feed.MovedTo); err != nil {
+			return err
+		}
+	}
+	if feed.Truncated > 0 {
+		fmt.Printf("Warning: feed %s has more than %d items, ignored the last %d\n", nextFeed.Url, len(feed.Channel.Item), feed.Truncated)
+	}
+	if feed.Recovered != nextFeed.ParseRecovered {
+		err = s.DB.SetFeedParseRecovered(context.Background(), database.SetFeedParseRecoveredParams{
+			ParseRecovered: feed.Recovered,
+			ID:             nextFeed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording parse recovery for feed %s: %w", nextFeed.Url, err)
+		}
+	}
+	transforms, err := loadTransforms(s, nextFeed.ID)
+	if err != nil {
+		return fmt.Errorf("error loading filters for feed %s: %w", nextFeed.Url, err)
+	}
+	listed := make([]string, 0, len(feed.Channel.Item))
 	for _, item := range feed.Channel.Item {
-		pubDate, err := parsePubDate(item.PubDate)
+		kept := rss.ApplyTransforms(&item, transforms)
+		listed = append(listed, item.Link)
+		if !kept {
+			fmt.Printf("Post dropped by filter: %s (%s)\n", item.Title, item.Link)
+			continue
+		}
+		// Posts removed by retention stay removed while the feed still lists them.
+		pruned, err := s.DB.IsPostPruned(context.Background(), item.Link)
 		if err != nil {
-			return fmt.Errorf("error parsing pubDate %s: %w", item.PubDate, err)
-		}
-		_, err = s.DB.CreatePost(context.Background(), database.CreatePostParams{
-			ID:          uuid.New(),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-			Title:       item.Title,
-			Url:         item.Link,
-			Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
-			PublishedAt: sql.NullTime{Time: pubDate, Valid: true},
-			FeedID:      nextFeed.ID,
+			return fmt.Errorf("error checking post %s: %w", item.Link, err)
+		}
+		if pruned {
+			continue
+		}
+		base, err := url.Parse(item.Base)
+		if err != nil {
+			return fmt.Errorf("invalid base url %s for post %s: %w", item.Base, item.Link, err)
+		}
+		item.Description = sanitize.HTML(item.Description, base)
+		item.Content = sanitize.HTML(item.Content, base)
+		image := item.Image()
+		pubDate, err := rss.ItemDate(item)
+		estimated := err != nil
+		if estimated {
+			fmt.Printf("No usable date for %s (%v), using fetch time\n", item.Link, err)
+			pubDate = time.Now()
+		}
+		post, err := s.DB.CreatePost(context.Background(), database.CreatePostParams{
+			ID:                   uuid.New(),
+			CreatedAt:            time.Now(),
+			UpdatedAt:            time.Now(),
+			Title:                item.Title,
+			Url:                  item.Link,
+			Description:          sql.NullString{String: item.Description, Valid: item.Description != ""},
+			PublishedAt:          sql.NullTime{Time: pubDate, Valid: true},
+			FeedID:               nextFeed.ID,
+			Content:              sql.NullString{String: item.Content, Valid: item.Content != ""},
+			PublishedAtEstimated: estimated,
+			ImageUrl:             sql.NullString{String: image, Valid: image != ""},
 		})
 		if err != nil {
 			// Ignore unique constraint violation on url
@@ -69,31 +197,80 @@ func scrapeFeeds(s *State) error {
 			}
 			return fmt.Errorf("error creating post %s: %w", item.Link, err)
 		}
+		for _, author := range item.Authors() {
+			err = s.DB.AddPostAuthor(context.Background(), database.AddPostAuthorParams{
+				PostID: post.ID,
+				Name:   author,
+			})
+			if err != nil {
+				return fmt.Errorf("error storing author of %s: %w", item.Link, err)
+			}
+		}
+		for _, tag := range append(item.Tags, item.Categories()...) {
+			err = s.DB.AddPostTag(context.Background(), database.AddPostTagParams{
+				PostID: post.ID,
+				Tag:    tag,
+			})
+			if err != nil {
+				return fmt.Errorf("error tagging post %s: %w", item.Link, err)
+			}
+		}
+		for _, enclosure := range item.Enclosures() {
+			err = s.DB.CreatePostEnclosure(context.Background(), database.CreatePostEnclosureParams{
+				ID:              uuid.New(),
+				CreatedAt:       time.Now(),
+				PostID:          post.ID,
+				Url:             enclosure.URL,
+				MimeType:        enclosure.Type,
+				LengthBytes:     sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0},
+				DurationSeconds: positiveInt32(int64(enclosure.Duration.Seconds())),
+			})
+			if err != nil {
+				return fmt.Errorf("error storing enclosure %s: %w", enclosure.URL, err)
+			}
+		}
+		if episode, ok := item.Episode(); ok {
+			err = s.DB.CreatePostEpisode(context.Background(), database.CreatePostEpisodeParams{
+				PostID:          post.ID,
+				DurationSeconds: positiveInt32(int64(episode.Duration.Seconds())),
+				Episode:         positiveInt32(int64(episode.Number)),
+				Season:          positiveInt32(int64(episode.Season)),
+				ImageUrl:        sql.NullString{String: episode.ImageURL, Valid: episode.ImageURL != ""},
+				TranscriptUrl:   sql.NullString{String: episode.TranscriptURL, Valid: episode.TranscriptURL != ""},
+				TranscriptType:  sql.NullString{String: episode.TranscriptType, Valid: episode.TranscriptType != ""},
+				ChaptersUrl:     sql.NullString{String: episode.ChaptersURL, Valid: episode.ChaptersURL != ""},
+			})
+			if err != nil {
+				return fmt.Errorf("error storing episode metadata for %s: %w", item.Link, err)
+			}
+		}
+		if image != "" && s.Config.Images.Cache {
+			// A missing thumbnail is not worth failing the scrape over.
+			path, err := cacheImage(context.Background(), s, post.ID, image)
+			if err != nil {
+				fmt.Printf("Warning: could not cache image %s: %v\n", image, err)
+			} else {
+				err = s.DB.SetPostImagePath(context.Background(), database.SetPostImagePathParams{
+					ID:        post.ID,
+					ImagePath: sql.NullString{String: path, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("error recording cached image for %s: %w", item.Link, err)
+				}
+			}
+		}
 		fmt.Printf("Post created: %s (%s)\n", item.Title, item.Link)
 	}
-	return nil
-}
-
-func parsePubDate(pubDate string) (time.Time, error) {
-	layouts := []string{
-		time.RFC1123,  // "Mon, 02 Jan 2006 15:04:05 MST"
-		time.RFC1123Z, // "Mon, 02 Jan 2006 15:04:05 -0700"
-		time.RFC822,   // "02 Jan 06 15:04 MST"
-		time.RFC822Z,  // "02 Jan 06 15:04 -0700"
-		time.RFC850,   // "Monday, 02-Jan-06 15:04:05 MST"
-		time.RFC3339,  // Atom feeds sometimes use this
-	}
-
-	// Normalize common timezone abbreviations
-	pubDate = strings.Replace(pubDate, "GMT", "UTC", 1)
-
-	var t time.Time
-	var err error
-	for _, layout := range layouts {
-		t, err = time.Parse(layout, pubDate)
-		if err == nil {
-			return t, nil
+	// Pruned posts the feed no longer lists cannot come back, so they need
+	// not be remembered. Items past a truncation are still listed upstream.
+	if feed.Truncated == 0 {
+		_, err = s.DB.TrimPrunedPosts(context.Background(), database.TrimPrunedPostsParams{
+			FeedID:     nextFeed.ID,
+			ListedUrls: listed,
+		})
+		if err != nil {
+			return fmt.Errorf("error forgetting pruned posts of feed %s: %w", nextFeed.Url, err)
 		}
 	}
-	return time.Time{}, err
+	return nil
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+)
+
+// findFeed finds a feed by its URL, ignoring differences that do not change
+// the feed (see rss.CanonicalURL). Canonical URLs written by the migration
+// that introduced them are approximate until `feeds dedupe` runs, so the
+// exact URL is tried as well. It returns sql.ErrNoRows if there is no match.
+func findFeed(s *State, feedURL string) (database.Feed, error) {
+	canonical, err := rss.CanonicalURL(feedURL)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("invalid feed url %s: %w", feedURL, err)
+	}
+	normalized, err := rss.NormalizeURL(feedURL)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("invalid feed url %s: %w", feedURL, err)
+	}
+	feed, err := s.DB.GetFeedByCanonicalUrl(context.Background(), canonical)
+	if err == sql.ErrNoRows {
+		feed, err = s.DB.GetFeedByUrl(context.Background(), normalized)
+	}
+	if err == sql.ErrNoRows && normalized != feedURL {
+		feed, err = s.DB.GetFeedByUrl(context.Background(), feedURL)
+	}
+	return feed, err
+}
+
+// lookupFeed finds a feed by its URL (see findFeed) or by a URL it used to
+// have before a permanent redirect or a merge.
+func lookupFeed(s *State, feedURL string) (database.Feed, error) {
+	feed, err := findFeed(s, feedURL)
+	if err == nil {
+		return feed, nil
+	}
+	if err != sql.ErrNoRows {
+		return database.Feed{}, fmt.Errorf("error fetching feed: %w", err)
+	}
+	normalized, err := rss.NormalizeURL(feedURL)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("invalid feed url %s: %w", feedURL, err)
+	}
+	feed, err = s.DB.GetFeedByAlias(context.Background(), normalized)
+	if err == sql.ErrNoRows && normalized != feedURL {
+		// Aliases recorded before URLs were normalized.
+		feed, err = s.DB.GetFeedByAlias(context.Background(), feedURL)
+	}
+	if err == sql.ErrNoRows {
+		return database.Feed{}, fmt.Errorf("feed %s not found", feedURL)
+	}
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("error fetching feed: %w", err)
+	}
+	return feed, nil
+}
+
+// moveFeed records that feed permanently moved to newURL, keeping its old URL
+// as an alias. If another feed already uses newURL, or newURL is on another
+// host and the feed has credentials that would then be sent there, the move
+// is only reported.
+func moveFeed(s *State, feed database.Feed, newURL string) error {
+	normalized, err := rss.NormalizeURL(newURL)
+	if err != nil {
+		return fmt.Errorf("invalid redirect target %s: %w", newURL, err)
+	}
+	if normalized == feed.Url {
+		return nil
+	}
+	same, err := sameHost(feed.Url, normalized)
+	if err != nil {
+		return err
+	}
+	if !same {
+		creds, err := s.DB.ListFeedCredentials(context.Background(), feed.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching credentials of feed %s: %w", feed.Url, err)
+		}
+		if len(creds) > 0 {
+			fmt.Printf("Warning: feed %s redirects to %s on another host; not moving it so its credentials are not sent there\n", feed.Url, normalized)
+			return nil
+		}
+	}
+	existing, err := conflictingFeed(s, feed, normalized)
+	if err == nil {
+		fmt.Printf("Warning: feed %s redirects to %s, which is already the feed %s\n", feed.Url, normalized, existing.Name)
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	if err := changeFeedURL(s, feed, normalized); err != nil {
+		return err
+	}
+	fmt.Printf("Feed %s moved permanently to %s\n", feed.Url, normalized)
+	return nil
+}
+
+// conflictingFeed returns the feed other than feed that newURL already
+// belongs to, by its URL or an alias, or sql.ErrNoRows if there is none.
+func conflictingFeed(s *State, feed database.Feed, newURL string) (database.Feed, error) {
+	existing, err := findFeed(s, newURL)
+	if err == sql.ErrNoRows {
+		existing, err = s.DB.GetFeedByAlias(context.Background(), newURL)
+	}
+	if err == sql.ErrNoRows || (err == nil && existing.ID == feed.ID) {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("error checking for feed %s: %w", newURL, err)
+	}
+	return existing, nil
+}
+
+// changeFeedURL points feed at the normalized newURL and keeps its old URL
+// as an alias, so it can still be found by it.
+func changeFeedURL(s *State, feed database.Feed, newURL string) error {
+	canonical, err := rss.CanonicalURL(newURL)
+	if err != nil {
+		return fmt.Errorf("invalid feed url %s: %w", newURL, err)
+	}
+	return s.inTx(func(q *database.Queries) error {
+		err := q.CreateFeedAlias(context.Background(), database.CreateFeedAliasParams{
+			Url:       feed.Url,
+			CreatedAt: time.Now(),
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording alias %s: %w", feed.Url, err)
+		}
+		// The feed may be going back to a URL it used before.
+		if err := q.DeleteFeedAlias(context.Background(), newURL); err != nil {
+			return fmt.Errorf("error removing alias %s: %w", newURL, err)
+		}
+		err = q.UpdateFeedUrl(context.Background(), database.UpdateFeedUrlParams{
+			Url:          newURL,
+			CanonicalUrl: canonical,
+			UpdatedAt:    time.Now(),
+			ID:           feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating url of feed %s: %w", feed.Url, err)
+		}
+		return nil
+	})
+}
+
+// sameHost reports whether two feed URLs have the same host name.
+func sameHost(a, b string) (bool, error) {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false, fmt.Errorf("invalid feed url %s: %w", a, err)
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false, fmt.Errorf("invalid feed url %s: %w", b, err)
+	}
+	return strings.EqualFold(ua.Hostname(), ub.Hostname()), nil
+}
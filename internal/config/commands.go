@@ -15,10 +15,21 @@ func NewCommands() *commands {
 	c.register("agg", handlerAgg)
 	c.register("addfeed", middlewareLoggedIn(handlerAddFeed))
 	c.register("feeds", handlerFeeds)
+	c.register("feed", middlewareLoggedIn(handlerFeed))
 	c.register("follow", middlewareLoggedIn(handlerFollow))
 	c.register("following", middlewareLoggedIn(handlerFollowing))
 	c.register("unfollow", middlewareLoggedIn(handlerUnfollow))
 	c.register("browse", middlewareLoggedIn(handlerBrowse))
+	c.register("search", middlewareLoggedIn(handlerSearch))
+	c.register("show", handlerShow)
+	c.register("download", handlerDownload)
+	c.register("episodes", middlewareLoggedIn(handlerEpisodes))
+	c.register("prune", handlerPrune)
+	c.register("retention", middlewareLoggedIn(handlerRetention))
+	c.register("keep", middlewareLoggedIn(handlerKeep))
+	c.register("unkeep", middlewareLoggedIn(handlerUnkeep))
+	c.register("rule", middlewareLoggedIn(handlerRule))
+	c.register("filter", middlewareLoggedIn(handlerFilter))
 	return c
 }
 
@@ -9,8 +9,54 @@ import (
 const configFileName = ".gatorconfig.json"
 
 type Config struct {
-	DbUrl           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DbUrl           string          `json:"db_url"`
+	CurrentUserName string          `json:"current_user_name"`
+	Retention       RetentionConfig `json:"retention,omitzero"`
+	Admins          []string        `json:"admins,omitempty"`
+	// OrphanGrace is how long a feed without followers is kept before it is
+	// removed, as a Go duration. Defaults to 30 days.
+	OrphanGrace string       `json:"orphan_grace,omitempty"`
+	Images      ImagesConfig `json:"images,omitzero"`
+	Fetch       FetchConfig  `json:"fetch,omitzero"`
+	// SecretKey encrypts stored feed credentials. The GATOR_SECRET_KEY
+	// environment variable takes precedence.
+	SecretKey string `json:"secret_key,omitempty"`
+}
+
+// RetentionConfig is the global retention policy applied to every feed that
+// does not override it. Zero values disable the corresponding limit.
+type RetentionConfig struct {
+	MaxPosts  int    `json:"max_posts,omitempty"`
+	MaxAge    string `json:"max_age,omitempty"`
+	AutoPrune bool   `json:"auto_prune,omitempty"`
+}
+
+// ImagesConfig controls local caching of post lead images. Images are only
+// downloaded when Cache is set; Dir defaults to ~/.gator/images and MaxBytes
+// to 5 MiB.
+type ImagesConfig struct {
+	Cache    bool   `json:"cache,omitempty"`
+	Dir      string `json:"dir,omitempty"`
+	MaxBytes int64  `json:"max_bytes,omitempty"`
+}
+
+// FetchConfig configures the HTTP client used for feeds, images and
+// downloads. Zero limits use the defaults in rss.DefaultLimits.
+type FetchConfig struct {
+	Timeout   string `json:"timeout,omitempty"`
+	MaxBytes  int64  `json:"max_bytes,omitempty"`
+	MaxItems  int    `json:"max_items,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Proxy     string `json:"proxy,omitempty"`
+	// HostRate and HostBurst configure the per-host token bucket, in
+	// requests per second and requests at once. HostRate is a pointer so
+	// that an explicit 0, which disables rate limiting, differs from unset.
+	HostRate        *float64 `json:"host_rate,omitempty"`
+	HostBurst       int      `json:"host_burst,omitempty"`
+	MaxConnsPerHost int      `json:"max_conns_per_host,omitempty"`
+	// AllowedNetworks lists IP addresses or CIDR ranges that may be fetched
+	// even though they are internal, e.g. a feed server on the LAN.
+	AllowedNetworks []string `json:"allowed_networks,omitempty"`
 }
 
 func ReadConfig() (*Config, error) {
@@ -42,9 +88,14 @@ func (c *Config) SetUser(userName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-	err = os.WriteFile(configFilePath, file, os.FileMode(0644))
+	// The config may hold secret_key, so keep it private to its owner. The
+	// mode passed to WriteFile only applies when the file is created.
+	err = os.WriteFile(configFilePath, file, os.FileMode(0600))
 	if err != nil {
 		return fmt.Errorf("failed to write config file %s: %w", configFilePath, err)
 	}
+	if err := os.Chmod(configFilePath, os.FileMode(0600)); err != nil {
+		return fmt.Errorf("failed to restrict permissions of config file %s: %w", configFilePath, err)
+	}
 	return nil
 }
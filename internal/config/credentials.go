@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/JonahLargen/BlogAggregator/internal/secrets"
+	"github.com/google/uuid"
+)
+
+const secretKeyEnv = "GATOR_SECRET_KEY"
+
+// secretBox returns the box used to encrypt feed credentials, keyed from the
+// environment or the config file.
+func secretBox(s *State) (*secrets.Box, error) {
+	key := os.Getenv(secretKeyEnv)
+	if key == "" {
+		key = s.Config.SecretKey
+	}
+	if key == "" {
+		return nil, fmt.Errorf("feed credentials need a secret key: set %s or secret_key in the config file", secretKeyEnv)
+	}
+	return secrets.NewBox(key)
+}
+
+// loadCredentials decrypts the credentials stored for a feed.
+func loadCredentials(s *State, feedID uuid.UUID) (rss.Credentials, error) {
+	var creds rss.Credentials
+	stored, err := s.DB.ListFeedCredentials(context.Background(), feedID)
+	if err != nil {
+		return creds, fmt.Errorf("error fetching feed credentials: %w", err)
+	}
+	if len(stored) == 0 {
+		return creds, nil
+	}
+	box, err := secretBox(s)
+	if err != nil {
+		return creds, err
+	}
+	for _, cred := range stored {
+		plain, err := box.Open(cred.Secret)
+		if err != nil {
+			return creds, fmt.Errorf("%s credential: %w", cred.Kind, err)
+		}
+		value := string(plain)
+		switch cred.Kind {
+		case "basic":
+			creds.Username, creds.Password, _ = strings.Cut(value, ":")
+		case "bearer":
+			creds.BearerToken = value
+		case "query":
+			if creds.Query == nil {
+				creds.Query = url.Values{}
+			}
+			creds.Query.Set(cred.Name, value)
+		case "header":
+			if creds.Header == nil {
+				creds.Header = http.Header{}
+			}
+			creds.Header.Set(cred.Name, value)
+		}
+	}
+	return creds, nil
+}
+
+func handlerFeedAuth(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return fmt.Errorf("feed auth requires a subcommand (set, list or clear) and a feed url")
+	}
+	action, feedURL, args := cmd.Args[0], cmd.Args[1], cmd.Args[2:]
+	feed, err := lookupFeed(s, feedURL)
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can manage its credentials")
+	}
+	switch action {
+	case "set":
+		return setFeedCredential(s, feed, args)
+	case "list":
+		stored, err := s.DB.ListFeedCredentials(context.Background(), feed.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching feed credentials: %w", err)
+		}
+		if len(stored) == 0 {
+			fmt.Printf("No credentials stored for feed %s\n", feed.Url)
+			return nil
+		}
+		fmt.Printf("Credentials for feed %s (values are never shown):\n", feed.Url)
+		for _, cred := range stored {
+			if cred.Name != "" {
+				fmt.Printf("- %s %s (updated %s)\n", cred.Kind, cred.Name, cred.UpdatedAt.Format("Jan 2, 2006"))
+			} else {
+				fmt.Printf("- %s (updated %s)\n", cred.Kind, cred.UpdatedAt.Format("Jan 2, 2006"))
+			}
+		}
+		return nil
+	case "clear":
+		removed, err := s.DB.DeleteFeedCredentials(context.Background(), feed.ID)
+		if err != nil {
+			return fmt.Errorf("error removing feed credentials: %w", err)
+		}
+		fmt.Printf("Removed %d credentials from feed %s\n", removed, feed.Url)
+		return nil
+	default:
+		return fmt.Errorf("unknown feed auth subcommand %s", action)
+	}
+}
+
+// setFeedCredential encrypts and stores one credential. A secret given as
+// "-" is read from standard input so it stays out of shell history.
+func setFeedCredential(s *State, feed database.Feed, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("feed auth set requires a kind: basic, bearer, query or header")
+	}
+	kind, args := args[0], args[1:]
+	var name, value string
+	switch kind {
+	case "basic":
+		if len(args) < 2 {
+			return fmt.Errorf("basic credentials require a username and a password")
+		}
+		password, err := readSecret(args[1])
+		if err != nil {
+			return err
+		}
+		value = args[0] + ":" + password
+	case "bearer":
+		if len(args) < 1 {
+			return fmt.Errorf("bearer credentials require a token")
+		}
+		token, err := readSecret(args[0])
+		if err != nil {
+			return err
+		}
+		value = token
+	case "query", "header":
+		if len(args) < 2 {
+			return fmt.Errorf("%s credentials require a name and a value", kind)
+		}
+		secret, err := readSecret(args[1])
+		if err != nil {
+			return err
+		}
+		name, value = args[0], secret
+	default:
+		return fmt.Errorf("unknown credential kind %s", kind)
+	}
+	box, err := secretBox(s)
+	if err != nil {
+		return err
+	}
+	sealed, err := box.Seal([]byte(value))
+	if err != nil {
+		return err
+	}
+	err = s.DB.UpsertFeedCredential(context.Background(), database.UpsertFeedCredentialParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		FeedID:    feed.ID,
+		Kind:      kind,
+		Name:      name,
+		Secret:    sealed,
+	})
+	if err != nil {
+		return fmt.Errorf("error storing credential: %w", err)
+	}
+	fmt.Printf("Stored %s credential for feed %s\n", kind, feed.Url)
+	return nil
+}
+
+func readSecret(arg string) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("error reading secret from stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/google/uuid"
+)
+
+// mergeCounts reports what merging a duplicate feed moved, and what it had
+// to drop because the kept feed already had its own.
+type mergeCounts struct {
+	follows            int64
+	posts              int64
+	droppedCredentials int
+	droppedRetention   bool
+}
+
+// mergeFeed moves the follows, posts, rules, filters, credentials, retention
+// override and aliases of a duplicate feed to keeperID, keeps the
+// duplicate's URL as an alias and deletes it, all in one transaction. Users
+// following both feeds keep a single follow, and credentials or a retention
+// override the kept feed already has win over the duplicate's.
+func mergeFeed(s *State, keeperID uuid.UUID, duplicate database.ListFeedsRow) (mergeCounts, error) {
+	var counts mergeCounts
+	err := s.inTx(func(q *database.Queries) error {
+		ctx := context.Background()
+		var err error
+		counts.follows, err = q.MoveFeedFollows(ctx, database.MoveFeedFollowsParams{KeeperID: keeperID, DuplicateID: duplicate.ID})
+		if err != nil {
+			return fmt.Errorf("error moving follows of feed %s: %w", duplicate.Url, err)
+		}
+		counts.posts, err = q.MoveFeedPosts(ctx, database.MoveFeedPostsParams{KeeperID: keeperID, DuplicateID: duplicate.ID})
+		if err != nil {
+			return fmt.Errorf("error moving posts of feed %s: %w", duplicate.Url, err)
+		}
+		if _, err := q.MoveFeedRules(ctx, database.MoveFeedRulesParams{KeeperID: keeperID, DuplicateID: duplicate.ID}); err != nil {
+			return fmt.Errorf("error moving rules of feed %s: %w", duplicate.Url, err)
+		}
+		if _, err := q.MoveFeedFilters(ctx, database.MoveFeedFiltersParams{KeeperID: keeperID, DuplicateID: duplicate.ID}); err != nil {
+			return fmt.Errorf("error moving filters of feed %s: %w", duplicate.Url, err)
+		}
+		if _, err := q.MoveFeedCredentials(ctx, database.MoveFeedCredentialsParams{KeeperID: keeperID, DuplicateID: duplicate.ID}); err != nil {
+			return fmt.Errorf("error moving credentials of feed %s: %w", duplicate.Url, err)
+		}
+		left, err := q.ListFeedCredentials(ctx, duplicate.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching credentials of feed %s: %w", duplicate.Url, err)
+		}
+		counts.droppedCredentials = len(left)
+		if _, err := q.MoveFeedRetention(ctx, database.MoveFeedRetentionParams{KeeperID: keeperID, DuplicateID: duplicate.ID}); err != nil {
+			return fmt.Errorf("error moving retention of feed %s: %w", duplicate.Url, err)
+		}
+		_, err = q.GetFeedRetention(ctx, duplicate.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("error fetching retention of feed %s: %w", duplicate.Url, err)
+		}
+		counts.droppedRetention = err == nil
+		if err := q.MoveFeedAliases(ctx, database.MoveFeedAliasesParams{KeeperID: keeperID, DuplicateID: duplicate.ID}); err != nil {
+			return fmt.Errorf("error moving aliases of feed %s: %w", duplicate.Url, err)
+		}
+		err = q.CreateFeedAlias(ctx, database.CreateFeedAliasParams{
+			Url:       duplicate.Url,
+			CreatedAt: time.Now(),
+			FeedID:    keeperID,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording alias %s: %w", duplicate.Url, err)
+		}
+		if err := q.DeleteFeed(ctx, duplicate.ID); err != nil {
+			return fmt.Errorf("error deleting feed %s: %w", duplicate.Url, err)
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// handlerFeedsDedupe merges feeds whose URLs have the same canonical form
+// into the oldest of them and brings every feed's stored canonical URL up to
+// date.
+func handlerFeedsDedupe(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	dryRun := fs.Bool("dry-run", false, "only report the feeds that would be merged")
+	if _, err := parseFlags(fs, cmd.Args); err != nil {
+		return err
+	}
+	if !isAdmin(s, user) {
+		return fmt.Errorf("only admins can merge duplicate feeds")
+	}
+	feeds, err := s.DB.ListFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("error listing feeds: %w", err)
+	}
+	// ListFeeds is newest first; the oldest feed of each group is kept.
+	slices.Reverse(feeds)
+	groups := make(map[string][]database.ListFeedsRow)
+	var order []string
+	for _, feed := range feeds {
+		canonical, err := rss.CanonicalURL(feed.Url)
+		if err != nil {
+			fmt.Printf("Skipping feed %s: %v\n", feed.Url, err)
+			continue
+		}
+		if _, ok := groups[canonical]; !ok {
+			order = append(order, canonical)
+		}
+		groups[canonical] = append(groups[canonical], feed)
+	}
+
+	var merged, totalFollows, totalPosts int64
+	var stale []database.ListFeedsRow
+	for _, canonical := range order {
+		group := groups[canonical]
+		keeper := group[0]
+		for _, duplicate := range group[1:] {
+			var follows, posts int64
+			if *dryRun {
+				if follows, err = s.DB.CountFeedFollows(context.Background(), duplicate.ID); err == nil {
+					posts, err = s.DB.CountFeedPosts(context.Background(), duplicate.ID)
+				}
+				if err != nil {
+					return fmt.Errorf("error counting follows and posts of feed %s: %w", duplicate.Url, err)
+				}
+				fmt.Printf("Would merge %s into %s (%d follows, %d posts)\n", duplicate.Url, keeper.Url, follows, posts)
+			} else {
+				counts, err := mergeFeed(s, keeper.ID, duplicate)
+				if err != nil {
+					return err
+				}
+				follows, posts = counts.follows, counts.posts
+				fmt.Printf("Merged %s into %s (%d follows, %d posts moved)\n", duplicate.Url, keeper.Url, follows, posts)
+				if counts.droppedCredentials > 0 {
+					fmt.Printf("  Dropped %d credentials of %s; %s already has credentials of the same kind\n", counts.droppedCredentials, duplicate.Url, keeper.Url)
+				}
+				if counts.droppedRetention {
+					fmt.Printf("  Dropped the retention override of %s; %s has its own\n", duplicate.Url, keeper.Url)
+				}
+			}
+			merged++
+			totalFollows += follows
+			totalPosts += posts
+		}
+		if keeper.CanonicalUrl != canonical {
+			keeper.CanonicalUrl = canonical
+			stale = append(stale, keeper)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("Would merge %d duplicate feeds (%d follows, %d posts) and update %d canonical URLs\n",
+			merged, totalFollows, totalPosts, len(stale))
+		return nil
+	}
+	if err := updateCanonicalURLs(s, stale); err != nil {
+		return err
+	}
+	fmt.Printf("Merged %d duplicate feeds (%d follows, %d posts) and updated %d canonical URLs\n",
+		merged, totalFollows, totalPosts, len(stale))
+	return nil
+}
+
+// updateCanonicalURLs stores the recomputed canonical URL of each feed. A
+// feed may briefly collide with another whose canonical URL is also about to
+// change, so failed updates are retried for as long as others succeed.
+func updateCanonicalURLs(s *State, feeds []database.ListFeedsRow) error {
+	for len(feeds) > 0 {
+		var retry []database.ListFeedsRow
+		var lastErr error
+		for _, feed := range feeds {
+			err := s.DB.SetFeedCanonicalUrl(context.Background(), database.SetFeedCanonicalUrlParams{
+				CanonicalUrl: feed.CanonicalUrl,
+				UpdatedAt:    time.Now(),
+				ID:           feed.ID,
+			})
+			if err != nil {
+				retry = append(retry, feed)
+				lastErr = fmt.Errorf("error updating canonical url of feed %s: %w", feed.Url, err)
+			}
+		}
+		if len(retry) == len(feeds) {
+			return lastErr
+		}
+		feeds = retry
+	}
+	return nil
+}
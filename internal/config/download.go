@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/google/uuid"
+)
+
+// printEnclosures lists a post's media files beneath it.
+func printEnclosures(s *State, postID uuid.UUID) error {
+	enclosures, err := s.DB.ListEnclosuresForPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("error fetching enclosures: %w", err)
+	}
+	for _, enclosure := range enclosures {
+		fmt.Printf("Enclosure: %s%s\n", enclosure.Url, describeEnclosure(enclosure))
+	}
+	return nil
+}
+
+func describeEnclosure(enclosure database.PostEnclosure) string {
+	var details []string
+	if enclosure.MimeType != "" {
+		details = append(details, enclosure.MimeType)
+	}
+	if enclosure.LengthBytes.Valid {
+		details = append(details, formatBytes(enclosure.LengthBytes.Int64))
+	}
+	if enclosure.DurationSeconds.Valid {
+		details = append(details, (time.Duration(enclosure.DurationSeconds.Int32) * time.Second).String())
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func handlerDownload(s *State, cmd Command) error {
+	fs := newFlagSet(cmd.Name)
+	dir := fs.String("o", ".", "directory to save the enclosure in")
+	index := fs.Int("n", 1, "which enclosure to download when a post has several")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("download command requires a post id or url argument")
+	}
+	post, err := lookupPost(s, args[0])
+	if err != nil {
+		return err
+	}
+	enclosures, err := s.DB.ListEnclosuresForPost(context.Background(), post.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching enclosures: %w", err)
+	}
+	if len(enclosures) == 0 {
+		return fmt.Errorf("post %s has no enclosures", post.Title)
+	}
+	if *index < 1 || *index > len(enclosures) {
+		return fmt.Errorf("post %s has %d enclosures, cannot download number %d", post.Title, len(enclosures), *index)
+	}
+	enclosure := enclosures[*index-1]
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", *dir, err)
+	}
+	dest := filepath.Join(*dir, enclosureFileName(enclosure))
+	written, err := downloadFile(context.Background(), s.Fetcher, enclosure.Url, dest)
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", enclosure.Url, err)
+	}
+	fmt.Printf("Downloaded %s to %s\n", formatBytes(written), dest)
+	return nil
+}
+
+// enclosureFileName names the downloaded file after the last segment of the
+// enclosure's URL, falling back to the enclosure ID when that segment could
+// escape the download directory.
+func enclosureFileName(enclosure database.PostEnclosure) string {
+	if u, err := url.Parse(enclosure.Url); err == nil {
+		name := path.Base(u.Path)
+		if name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`) {
+			return name
+		}
+	}
+	return enclosure.ID.String()
+}
+
+// downloadFile streams rawURL into dest, resuming from a previous partial
+// download in dest+".part" when the server supports range requests. It
+// returns the total size of the file.
+func downloadFile(ctx context.Context, fetcher *rss.Fetcher, rawURL, dest string) (int64, error) {
+	partial := dest + ".part"
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	resp, err := fetcher.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if start, ok := rangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			if offset == 0 {
+				return 0, fmt.Errorf("unexpected partial response: Content-Range %q", resp.Header.Get("Content-Range"))
+			}
+			// The server did not resume where the partial file ends, so
+			// appending would corrupt it; start over instead.
+			fmt.Printf("Server did not resume at %s, restarting download\n", formatBytes(offset))
+			resp.Body.Close()
+			if err := os.Remove(partial); err != nil {
+				return 0, err
+			}
+			return downloadFile(ctx, fetcher, rawURL, dest)
+		}
+		fmt.Printf("Resuming download at %s\n", formatBytes(offset))
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		offset = 0
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete.
+		return offset, os.Rename(partial, dest)
+	default:
+		return 0, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	file, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("download interrupted after %s, run the command again to resume: %w", formatBytes(offset+written), err)
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return 0, err
+	}
+	return offset + written, nil
+}
+
+// rangeStart returns the first byte position of a Content-Range header such
+// as "bytes 100-199/200".
+func rangeStart(contentRange string) (int64, bool) {
+	spec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
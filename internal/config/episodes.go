@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func handlerEpisodes(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	feedURL := fs.String("feed", "", "only list episodes of this feed")
+	limit := fs.Int("limit", 20, "maximum number of episodes to list")
+	if _, err := parseFlags(fs, cmd.Args); err != nil {
+		return err
+	}
+	if *limit < 1 || *limit > math.MaxInt32 {
+		return fmt.Errorf("limit must be between 1 and %d", math.MaxInt32)
+	}
+	var feedID uuid.NullUUID
+	if *feedURL != "" {
+		feed, err := lookupFeed(s, *feedURL)
+		if err != nil {
+			return err
+		}
+		feedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	episodes, err := s.DB.ListEpisodes(context.Background(), database.ListEpisodesParams{
+		FeedID:     feedID,
+		UserID:     user.ID,
+		MaxResults: int32(*limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching episodes: %w", err)
+	}
+	if len(episodes) == 0 {
+		fmt.Println("No episodes found")
+		return nil
+	}
+	for _, episode := range episodes {
+		var number string
+		switch {
+		case episode.Season.Valid && episode.Episode.Valid:
+			number = fmt.Sprintf("S%02dE%02d", episode.Season.Int32, episode.Episode.Int32)
+		case episode.Episode.Valid:
+			number = fmt.Sprintf("E%02d", episode.Episode.Int32)
+		case episode.Season.Valid:
+			number = fmt.Sprintf("S%02d", episode.Season.Int32)
+		}
+		duration := "--"
+		if episode.DurationSeconds.Valid {
+			duration = (time.Duration(episode.DurationSeconds.Int32) * time.Second).String()
+		}
+		fmt.Printf("%-6s | %s | %s | %s from %s\n",
+			number,
+			episode.PublishedAt.Time.Format("Jan 2, 2006"),
+			duration,
+			episode.Title,
+			episode.FeedName,
+		)
+		fmt.Printf("       ID: %s\n", episode.ID)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+)
+
+// retireFeed stops polling a feed, which stays visible to its followers.
+func retireFeed(s *State, feed database.Feed, reason string) error {
+	err := s.DB.SetFeedRetiredAt(context.Background(), database.SetFeedRetiredAtParams{
+		RetiredAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error retiring feed %s: %w", feed.Url, err)
+	}
+	fmt.Printf("Retired feed %s (%s)\n", feed.Url, reason)
+	return nil
+}
+
+func handlerFeed(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("feed command requires a subcommand: stats, rename, set-url, delete, transfer, retire, revive or auth")
+	}
+	sub := Command{Name: cmd.Name + " " + cmd.Args[0], Args: cmd.Args[1:]}
+	switch cmd.Args[0] {
+	case "stats":
+		return handlerFeedStats(s, sub, user)
+	case "rename":
+		return handlerFeedRename(s, sub, user)
+	case "set-url":
+		return handlerFeedSetURL(s, sub, user)
+	case "delete":
+		return handlerFeedDelete(s, sub, user)
+	case "transfer":
+		return handlerFeedTransfer(s, sub, user)
+	case "retire":
+		return handlerFeedRetire(s, sub, user)
+	case "revive":
+		return handlerFeedRevive(s, sub, user)
+	case "auth":
+		return handlerFeedAuth(s, sub, user)
+	default:
+		return fmt.Errorf("unknown feed subcommand %s", cmd.Args[0])
+	}
+}
+
+func handlerFeedRename(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return fmt.Errorf("feed rename requires a feed url and a new name")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can rename it")
+	}
+	name := cmd.Args[1]
+	err = s.DB.RenameFeed(context.Background(), database.RenameFeedParams{
+		Name:      name,
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error renaming feed %s: %w", feed.Url, err)
+	}
+	fmt.Printf("Renamed feed %s from %q to %q\n", feed.Url, feed.Name, name)
+	return nil
+}
+
+func handlerFeedSetURL(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return fmt.Errorf("feed set-url requires the current feed url and a new url")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can change its url")
+	}
+	newURL, err := rss.NormalizeURL(cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("invalid feed url %s: %w", cmd.Args[1], err)
+	}
+	if err := rss.CheckURL(newURL); err != nil {
+		return err
+	}
+	if newURL == feed.Url {
+		fmt.Printf("Feed %s already uses %s\n", feed.Name, newURL)
+		return nil
+	}
+	existing, err := conflictingFeed(s, feed, newURL)
+	if err == nil {
+		return fmt.Errorf("feed %s already exists as %s", newURL, existing.Url)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	if err := changeFeedURL(s, feed, newURL); err != nil {
+		return err
+	}
+	fmt.Printf("Changed url of feed %s from %s to %s\n", feed.Name, feed.Url, newURL)
+	return nil
+}
+
+// handlerFeedDelete removes a feed. Its posts, follows, rules, filters,
+// credentials and aliases are removed with it, so the counts are reported.
+func handlerFeedDelete(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("feed delete requires a feed url argument")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can delete it")
+	}
+	follows, err := s.DB.CountFeedFollows(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("error counting follows of feed %s: %w", feed.Url, err)
+	}
+	posts, err := s.DB.CountFeedPosts(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("error counting posts of feed %s: %w", feed.Url, err)
+	}
+	if err := s.DB.DeleteFeed(context.Background(), feed.ID); err != nil {
+		return fmt.Errorf("error deleting feed %s: %w", feed.Url, err)
+	}
+	fmt.Printf("Deleted feed %s (%s)\n", feed.Name, feed.Url)
+	fmt.Printf("  Removed %d posts and %d follows, along with the feed's rules, filters, credentials and aliases\n", posts, follows)
+	if _, err := sweepImageCache(s); err != nil {
+		return fmt.Errorf("error cleaning image cache: %w", err)
+	}
+	return nil
+}
+
+func handlerFeedRetire(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("feed retire requires a feed url argument")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can retire it")
+	}
+	if feed.RetiredAt.Valid {
+		return fmt.Errorf("feed %s is already retired", feed.Url)
+	}
+	return retireFeed(s, feed, "retired by "+user.Name)
+}
+
+func handlerFeedRevive(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("feed revive requires a feed url argument")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can revive it")
+	}
+	if !feed.RetiredAt.Valid {
+		return fmt.Errorf("feed %s is not retired", feed.Url)
+	}
+	err = s.DB.SetFeedRetiredAt(context.Background(), database.SetFeedRetiredAtParams{
+		RetiredAt: sql.NullTime{},
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error reviving feed %s: %w", feed.Url, err)
+	}
+	fmt.Printf("Revived feed %s; it will be fetched again on the next agg cycle\n", feed.Url)
+	return nil
+}
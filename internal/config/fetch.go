@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+)
+
+// Politeness defaults used when the config leaves them unset.
+const (
+	defaultHostRate        = 1
+	defaultHostBurst       = 4
+	defaultMaxConnsPerHost = 2
+)
+
+// NewFetcher builds the shared HTTP fetcher from the fetch section of the
+// config.
+func NewFetcher(cfg FetchConfig) (*rss.Fetcher, error) {
+	limits := rss.Limits{
+		MaxBytes: cfg.MaxBytes,
+		MaxItems: cfg.MaxItems,
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch timeout %q in config: %w", cfg.Timeout, err)
+		}
+		limits.Timeout = timeout
+	}
+	fetcherConfig := rss.FetcherConfig{
+		UserAgent:       cfg.UserAgent,
+		Proxy:           cfg.Proxy,
+		Limits:          limits,
+		HostBurst:       cfg.HostBurst,
+		MaxConnsPerHost: cfg.MaxConnsPerHost,
+	}
+	for _, network := range cfg.AllowedNetworks {
+		prefix, err := parseNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %q in config: %w", network, err)
+		}
+		fetcherConfig.AllowedNetworks = append(fetcherConfig.AllowedNetworks, prefix)
+	}
+	// An explicit host_rate of 0 turns rate limiting off.
+	fetcherConfig.HostRate = defaultHostRate
+	if cfg.HostRate != nil {
+		fetcherConfig.HostRate = *cfg.HostRate
+	}
+	if fetcherConfig.HostBurst <= 0 {
+		fetcherConfig.HostBurst = defaultHostBurst
+	}
+	if fetcherConfig.MaxConnsPerHost <= 0 {
+		fetcherConfig.MaxConnsPerHost = defaultMaxConnsPerHost
+	}
+	return rss.NewFetcher(fetcherConfig)
+}
+
+// parseNetwork parses a CIDR range or a single IP address.
+func parseNetwork(network string) (netip.Prefix, error) {
+	if strings.Contains(network, "/") {
+		return netip.ParsePrefix(network)
+	}
+	addr, err := netip.ParseAddr(network)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/google/uuid"
+)
+
+// buildTransform turns a stored feed filter into an ingestion transform.
+func buildTransform(filter database.FeedFilter) (rss.Transform, error) {
+	switch filter.Kind {
+	case "drop":
+		re, err := regexp.Compile(filter.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", filter.Pattern, err)
+		}
+		return rss.DropMatching(re), nil
+	case "strip-tracking":
+		return rss.StripTrackingParams(trackingParams(filter.Pattern)...), nil
+	case "rewrite":
+		re, err := regexp.Compile(filter.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", filter.Pattern, err)
+		}
+		return rss.RewriteURL(re, filter.Replacement), nil
+	case "tag":
+		var re *regexp.Regexp
+		if filter.Pattern != "" {
+			var err error
+			re, err = regexp.Compile(filter.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", filter.Pattern, err)
+			}
+		}
+		return rss.Tag(filter.Replacement, re), nil
+	default:
+		return nil, fmt.Errorf("unknown filter kind %s", filter.Kind)
+	}
+}
+
+// trackingParams splits a comma-separated list of extra tracking parameter
+// names, ignoring surrounding spaces and empty names.
+func trackingParams(pattern string) []string {
+	var params []string
+	for _, name := range strings.Split(pattern, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			params = append(params, name)
+		}
+	}
+	return params
+}
+
+// loadTransforms builds the ingestion pipeline configured for a feed.
+func loadTransforms(s *State, feedID uuid.UUID) ([]rss.Transform, error) {
+	filters, err := s.DB.ListFeedFilters(context.Background(), feedID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching feed filters: %w", err)
+	}
+	transforms := make([]rss.Transform, 0, len(filters))
+	for _, filter := range filters {
+		t, err := buildTransform(filter)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %w", filter.ID, err)
+		}
+		transforms = append(transforms, t)
+	}
+	return transforms, nil
+}
+
+func handlerFilter(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("filter command requires a subcommand: add, list or rm")
+	}
+	sub := Command{Name: cmd.Name + " " + cmd.Args[0], Args: cmd.Args[1:]}
+	switch cmd.Args[0] {
+	case "add":
+		return handlerFilterAdd(s, sub, user)
+	case "list":
+		return handlerFilterList(s, sub, user)
+	case "rm":
+		return handlerFilterRemove(s, sub, user)
+	default:
+		return fmt.Errorf("unknown filter subcommand %s", cmd.Args[0])
+	}
+}
+
+func handlerFilterAdd(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return fmt.Errorf("filter add requires a feed url and a filter kind (drop, strip-tracking, rewrite or tag)")
+	}
+	feedURL, kind, args := cmd.Args[0], cmd.Args[1], cmd.Args[2:]
+	feed, err := lookupFeed(s, feedURL)
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can change its filters")
+	}
+	filter := database.FeedFilter{Kind: kind}
+	switch kind {
+	case "drop":
+		if len(args) < 1 {
+			return fmt.Errorf("drop filter requires a regex argument")
+		}
+		filter.Pattern = args[0]
+	case "strip-tracking":
+		if len(args) > 0 {
+			filter.Pattern = strings.Join(trackingParams(args[0]), ",")
+		}
+	case "rewrite":
+		if len(args) < 2 {
+			return fmt.Errorf("rewrite filter requires a regex and a replacement argument")
+		}
+		filter.Pattern = args[0]
+		filter.Replacement = args[1]
+	case "tag":
+		if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+			return fmt.Errorf("tag filter requires a tag argument")
+		}
+		filter.Replacement = strings.TrimSpace(args[0])
+		if len(args) > 1 {
+			filter.Pattern = args[1]
+		}
+	default:
+		return fmt.Errorf("unknown filter kind %s", kind)
+	}
+	if _, err := buildTransform(filter); err != nil {
+		return err
+	}
+	created, err := s.DB.CreateFeedFilter(context.Background(), database.CreateFeedFilterParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		FeedID:      feed.ID,
+		Kind:        filter.Kind,
+		Pattern:     filter.Pattern,
+		Replacement: filter.Replacement,
+	})
+	if err != nil {
+		return fmt.Errorf("error adding filter to feed %s: %w", feedURL, err)
+	}
+	fmt.Printf("Added %s filter %s to feed %s\n", created.Kind, created.ID, feedURL)
+	return nil
+}
+
+func handlerFilterList(s *State, cmd Command, _ database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("filter list requires a feed url argument")
+	}
+	feedURL := cmd.Args[0]
+	feed, err := lookupFeed(s, feedURL)
+	if err != nil {
+		return err
+	}
+	filters, err := s.DB.ListFeedFilters(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching feed filters: %w", err)
+	}
+	if len(filters) == 0 {
+		fmt.Printf("No filters configured for feed %s\n", feedURL)
+		return nil
+	}
+	fmt.Printf("Filters for feed %s (applied in order):\n", feedURL)
+	for _, filter := range filters {
+		fmt.Printf("- %s | %s | pattern: %q | replacement: %q\n",
+			filter.ID,
+			filter.Kind,
+			filter.Pattern,
+			filter.Replacement,
+		)
+	}
+	return nil
+}
+
+func handlerFilterRemove(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("filter rm requires a filter id argument")
+	}
+	id, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid filter id %s", cmd.Args[0])
+	}
+	filter, err := s.DB.GetFeedFilter(context.Background(), id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("filter %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching filter: %w", err)
+	}
+	feed, err := s.DB.GetFeedByID(context.Background(), filter.FeedID)
+	if err != nil {
+		return fmt.Errorf("error fetching feed: %w", err)
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can change its filters")
+	}
+	if err := s.DB.DeleteFeedFilter(context.Background(), id); err != nil {
+		return fmt.Errorf("error removing filter: %w", err)
+	}
+	fmt.Printf("Removed filter %s\n", id)
+	return nil
+}
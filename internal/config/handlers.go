@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/render"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
 	"github.com/google/uuid"
 )
 
@@ -70,6 +74,13 @@ func handlerReset(s *State, cmd Command) error {
 }
 
 func handlerListUsers(s *State, cmd Command) error {
+	if len(cmd.Args) > 0 {
+		if cmd.Args[0] != "delete" {
+			return fmt.Errorf("unknown users subcommand %s", cmd.Args[0])
+		}
+		sub := Command{Name: cmd.Name + " " + cmd.Args[0], Args: cmd.Args[1:]}
+		return middlewareLoggedIn(handlerUsersDelete)(s, sub)
+	}
 	users, err := s.DB.ListUsers(context.Background())
 	if err != nil {
 		return fmt.Errorf("error listing users: %w", err)
@@ -107,21 +118,39 @@ func handlerAddFeed(s *State, cmd Command, user database.User) error {
 		return fmt.Errorf("add feed command requires a feed name and feed URL argument")
 	}
 	feedName := cmd.Args[0]
-	feedURL := cmd.Args[1]
-	feed, err := s.DB.GetFeedByUrl(context.Background(), feedURL)
+	feedURL, err := rss.NormalizeURL(cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("invalid feed url %s: %w", cmd.Args[1], err)
+	}
+	if err := rss.CheckURL(feedURL); err != nil {
+		return err
+	}
+	canonical, err := rss.CanonicalURL(feedURL)
+	if err != nil {
+		return fmt.Errorf("invalid feed url %s: %w", feedURL, err)
+	}
+	feed, err := findFeed(s, cmd.Args[1])
 	if err != nil && err != sql.ErrNoRows {
 		return fmt.Errorf("error checking if feed exists: %w", err)
 	}
 	if feed.ID != uuid.Nil {
-		return fmt.Errorf("feed %s already exists", feedURL)
+		return fmt.Errorf("feed %s already exists as %s", feedURL, feed.Url)
+	}
+	feed, err = s.DB.GetFeedByAlias(context.Background(), feedURL)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error checking if feed exists: %w", err)
+	}
+	if feed.ID != uuid.Nil {
+		return fmt.Errorf("feed %s already exists and has moved to %s", feedURL, feed.Url)
 	}
 	feed, err = s.DB.CreateFeed(context.Background(), database.CreateFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Name:      feedName,
-		Url:       feedURL,
-		UserID:    user.ID,
+		ID:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		Name:         feedName,
+		Url:          feedURL,
+		UserID:       uuid.NullUUID{UUID: user.ID, Valid: true},
+		CanonicalUrl: canonical,
 	})
 	if err != nil {
 		return fmt.Errorf("error adding feed %s: %w", feedURL, err)
@@ -140,17 +169,44 @@ func handlerAddFeed(s *State, cmd Command, user database.User) error {
 	return nil
 }
 
-func handlerFeeds(s *State, _ Command) error {
+func handlerFeeds(s *State, cmd Command) error {
+	if len(cmd.Args) > 0 {
+		sub := Command{Name: cmd.Name + " " + cmd.Args[0], Args: cmd.Args[1:]}
+		switch cmd.Args[0] {
+		case "dedupe":
+			return middlewareLoggedIn(handlerFeedsDedupe)(s, sub)
+		case "gc":
+			return handlerFeedsGC(s, sub)
+		default:
+			return fmt.Errorf("unknown feeds subcommand %s", cmd.Args[0])
+		}
+	}
 	feeds, err := s.DB.ListFeeds(context.Background())
 	if err != nil {
 		return fmt.Errorf("error listing feeds: %w", err)
 	}
 	for _, feed := range feeds {
+		owner := feed.UserName.String
+		if !feed.UserName.Valid {
+			owner = systemOwner
+		}
 		fmt.Printf("Name: %s | URL: %s | User ID: %s\n",
 			feed.Name,
 			feed.Url,
-			feed.UserName,
+			owner,
 		)
+		if feed.ParseRecovered {
+			fmt.Println("  Warning: this feed is malformed; its owner should fix it")
+		}
+		if feed.RetiredAt.Valid {
+			fmt.Printf("  Retired on %s; no longer fetched\n", feed.RetiredAt.Time.Format("Jan 2, 2006"))
+		}
+		if feed.OrphanedAt.Valid {
+			fmt.Printf("  No followers since %s; will be removed after the grace period\n", feed.OrphanedAt.Time.Format("Jan 2, 2006"))
+		}
+		if feed.NextFetchAt.Valid && feed.NextFetchAt.Time.After(time.Now()) {
+			fmt.Printf("  Throttled by the server; next fetch after %s\n", feed.NextFetchAt.Time.Format(time.RFC1123))
+		}
 	}
 	if len(feeds) == 0 {
 		fmt.Println("No feeds found")
@@ -163,9 +219,9 @@ func handlerFollow(s *State, cmd Command, user database.User) error {
 		return fmt.Errorf("follow command requires a url argument")
 	}
 	feedURL := cmd.Args[0]
-	feed, err := s.DB.GetFeedByUrl(context.Background(), feedURL)
+	feed, err := lookupFeed(s, feedURL)
 	if err != nil {
-		return fmt.Errorf("feed %s not found", feedURL)
+		return err
 	}
 	_, err = s.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
@@ -177,7 +233,7 @@ func handlerFollow(s *State, cmd Command, user database.User) error {
 	if err != nil {
 		return fmt.Errorf("error following feed %s: %w", feedURL, err)
 	}
-	fmt.Printf("Followed feed %s\n", feedURL)
+	fmt.Printf("Followed feed %s\n", feed.Url)
 	return nil
 }
 
@@ -188,7 +244,13 @@ func handlerFollowing(s *State, _ Command, user database.User) error {
 	}
 	fmt.Println("Following feeds:")
 	for _, follow := range following {
-		fmt.Printf("- %s\n", follow)
+		fmt.Printf("- %s\n", follow.FeedName)
+		if follow.RetiredAt.Valid {
+			fmt.Printf("  Retired on %s; no new posts will be fetched. Unfollow with: gator unfollow %s\n",
+				follow.RetiredAt.Time.Format("Jan 2, 2006"),
+				follow.FeedUrl,
+			)
+		}
 	}
 	if len(following) == 0 {
 		fmt.Println("You are not following any feeds")
@@ -201,9 +263,9 @@ func handlerUnfollow(s *State, cmd Command, user database.User) error {
 		return fmt.Errorf("unfollow command requires a url argument")
 	}
 	feedURL := cmd.Args[0]
-	feed, err := s.DB.GetFeedByUrl(context.Background(), feedURL)
+	feed, err := lookupFeed(s, feedURL)
 	if err != nil {
-		return fmt.Errorf("feed %s not found", feedURL)
+		return err
 	}
 	_, err = s.DB.DeleteFeedFollow(context.Background(), database.DeleteFeedFollowParams{
 		FeedID: feed.ID,
@@ -212,33 +274,177 @@ func handlerUnfollow(s *State, cmd Command, user database.User) error {
 	if err != nil {
 		return fmt.Errorf("error unfollowing feed %s: %w", feedURL, err)
 	}
-	fmt.Printf("Unfollowed feed %s\n", feedURL)
+	fmt.Printf("Unfollowed feed %s\n", feed.Url)
 	return nil
 }
 
 func handlerBrowse(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	showMuted := fs.Bool("show-muted", false, "include posts hidden by mute rules")
+	raw := fs.Bool("raw", false, "print descriptions without rendering HTML")
+	author := fs.String("author", "", "only show posts by this author")
+	tag := fs.String("tag", "", "only show posts with this tag or category")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
 	limit := 2
-	var err error
-	if len(cmd.Args) > 0 {
-		limit, err = strconv.Atoi(cmd.Args[0])
+	if len(args) > 0 {
+		limit, err = strconv.Atoi(args[0])
 		if err != nil {
 			return fmt.Errorf("invalid limit value: %v", err)
 		}
 	}
-	posts, err := s.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit:  int32(limit),
-	})
+	rules, err := loadRules(s, user)
 	if err != nil {
-		return fmt.Errorf("error fetching posts: %w", err)
+		return err
+	}
+	var posts []database.GetPostsForUserRow
+	var highlighted []bool
+	muted := 0
+	for offset := 0; len(posts) < limit; offset += limit {
+		page, err := s.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID: user.ID,
+			Author: sql.NullString{String: *author, Valid: *author != ""},
+			Tag:    sql.NullString{String: *tag, Valid: *tag != ""},
+			Limit:  int32(limit),
+			Offset: int32(offset),
+		})
+		if err != nil {
+			return fmt.Errorf("error fetching posts: %w", err)
+		}
+		for _, post := range page {
+			if len(posts) == limit {
+				break
+			}
+			isMuted, isHighlighted := evaluateRules(rules, post.FeedID, post.Title, post.Description.String)
+			if isMuted && !*showMuted {
+				muted++
+				continue
+			}
+			posts = append(posts, post)
+			highlighted = append(highlighted, isHighlighted)
+		}
+		if len(page) < limit {
+			break
+		}
 	}
 	fmt.Printf("Found %d posts for user %s:\n", len(posts), user.Name)
-	for _, post := range posts {
-		fmt.Printf("%s from %s\n", post.PublishedAt.Time.Format("Mon Jan 2, 2006"), post.FeedName)
-		fmt.Printf("--- %s ---\n", post.Title)
-		fmt.Printf("    %v\n", post.Description.String)
+	for i, post := range posts {
+		fmt.Printf("%s from %s\n", formatPublished(post.PublishedAt, post.PublishedAtEstimated), post.FeedName)
+		if highlighted[i] {
+			fmt.Printf("*** %s ***\n", post.Title)
+		} else {
+			fmt.Printf("--- %s ---\n", post.Title)
+		}
+		if err := printAuthorsAndTags(s, post.ID); err != nil {
+			return err
+		}
+		fmt.Println(indent(formatBody(post.Description.String, *raw, terminalWidth()-4), "    "))
 		fmt.Printf("Link: %s\n", post.Url)
+		printImage(post.ImageUrl, post.ImagePath)
+		if err := printEnclosures(s, post.ID); err != nil {
+			return err
+		}
+		fmt.Printf("ID: %s\n", post.ID)
 		fmt.Println("=====================================")
 	}
+	if muted > 0 {
+		fmt.Printf("%d muted posts hidden (use --show-muted to include them)\n", muted)
+	}
 	return nil
 }
+
+// lookupPost finds a post by its ID or URL.
+func lookupPost(s *State, ref string) (database.Post, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		post, err := s.DB.GetPostByID(context.Background(), id)
+		if err == nil {
+			return post, nil
+		}
+		if err != sql.ErrNoRows {
+			return database.Post{}, fmt.Errorf("error fetching post: %w", err)
+		}
+	}
+	post, err := s.DB.GetPostByUrl(context.Background(), ref)
+	if err == sql.ErrNoRows {
+		return database.Post{}, fmt.Errorf("post %s not found", ref)
+	}
+	if err != nil {
+		return database.Post{}, fmt.Errorf("error fetching post: %w", err)
+	}
+	return post, nil
+}
+
+func handlerShow(s *State, cmd Command) error {
+	fs := newFlagSet(cmd.Name)
+	raw := fs.Bool("raw", false, "print the article without rendering HTML")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("show command requires a post id or url argument")
+	}
+	post, err := lookupPost(s, args[0])
+	if err != nil {
+		return err
+	}
+	feed, err := s.DB.GetFeedByID(context.Background(), post.FeedID)
+	if err != nil {
+		return fmt.Errorf("error fetching feed: %w", err)
+	}
+	fmt.Printf("%s from %s\n", formatPublished(post.PublishedAt, post.PublishedAtEstimated), feed.Name)
+	fmt.Printf("--- %s ---\n", post.Title)
+	if err := printAuthorsAndTags(s, post.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Link: %s\n", post.Url)
+	printImage(post.ImageUrl, post.ImagePath)
+	if err := printEnclosures(s, post.ID); err != nil {
+		return err
+	}
+	fmt.Println("=====================================")
+	body := post.Content.String
+	if !post.Content.Valid {
+		body = post.Description.String
+	}
+	fmt.Println(formatBody(body, *raw, terminalWidth()))
+	return nil
+}
+
+// formatPublished formats a post's publish date, noting when the feed gave no
+// usable date and the fetch time was used instead.
+func formatPublished(publishedAt sql.NullTime, estimated bool) string {
+	date := publishedAt.Time.Format("Mon Jan 2, 2006")
+	if estimated {
+		date += " (estimated)"
+	}
+	return date
+}
+
+// formatBody renders post HTML as terminal text unless raw output is requested.
+func formatBody(body string, raw bool, width int) string {
+	if raw {
+		return body
+	}
+	return render.HTML(body, width)
+}
+
+// terminalWidth returns the width to wrap output at, honoring $COLUMNS.
+func terminalWidth() int {
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
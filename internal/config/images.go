@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultImageMaxBytes = 5 << 20
+	imageTimeout         = 30 * time.Second
+)
+
+// printImage prints a post's lead image and where it is cached, if anywhere.
+func printImage(imageURL, imagePath sql.NullString) {
+	if !imageURL.Valid {
+		return
+	}
+	if imagePath.Valid {
+		fmt.Printf("Image: %s (cached at %s)\n", imageURL.String, imagePath.String)
+		return
+	}
+	fmt.Printf("Image: %s\n", imageURL.String)
+}
+
+// imageCacheDir returns the directory cached images are stored in.
+func imageCacheDir(s *State) (string, error) {
+	if s.Config.Images.Dir != "" {
+		return s.Config.Images.Dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".gator", "images"), nil
+}
+
+// cacheImage downloads a post's lead image into the image cache and returns
+// the path it was saved to. Images that are not image/* or are larger than the
+// configured limit are rejected.
+func cacheImage(ctx context.Context, s *State, postID uuid.UUID, rawURL string) (string, error) {
+	maxBytes := s.Config.Images.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultImageMaxBytes
+	}
+	dir, err := imageCacheDir(s)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, imageTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.Fetcher.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("not an image: %q", resp.Header.Get("Content-Type"))
+	}
+	if resp.ContentLength > maxBytes {
+		return "", fmt.Errorf("image is %s, larger than the %s limit", formatBytes(resp.ContentLength), formatBytes(maxBytes))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > maxBytes {
+		return "", fmt.Errorf("image is larger than the %s limit", formatBytes(maxBytes))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+	dest := filepath.Join(dir, postID.String()+imageExtension(mediaType, rawURL))
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// imageExtension picks a file extension from the image's URL, falling back to
+// its media type.
+func imageExtension(mediaType, rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		if ext := strings.ToLower(path.Ext(u.Path)); len(ext) > 1 && len(ext) <= 5 {
+			return ext
+		}
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// sweepImageCache deletes cached images whose post no longer exists, such as
+// after pruning or deleting a feed, and returns how many were removed. Only
+// files named after a post ID are touched, since the directory is
+// configurable, and recent files are left for the agg that saved them.
+func sweepImageCache(s *State) (int, error) {
+	dir, err := imageCacheDir(s)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading image cache %s: %w", dir, err)
+	}
+	paths, err := s.DB.ListPostImagePaths(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("error listing cached images: %w", err)
+	}
+	referenced := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		referenced[filepath.Base(p.String)] = true
+	}
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || referenced[name] {
+			continue
+		}
+		// A concurrent agg may not have recorded an image it just saved.
+		if info, err := entry.Info(); err != nil || time.Since(info.ModTime()) < imageTimeout {
+			continue
+		}
+		if _, err := uuid.Parse(strings.TrimSuffix(name, filepath.Ext(name))); err != nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, fmt.Errorf("error removing cached image %s: %w", name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 
 	"github.com/JonahLargen/BlogAggregator/internal/database"
 	"github.com/google/uuid"
@@ -24,3 +25,14 @@ func middlewareLoggedIn(handler func(s *State, cmd Command, user database.User)
 		return handler(s, cmd, user)
 	}
 }
+
+// isAdmin reports whether the user is listed in the config's admins.
+func isAdmin(s *State, user database.User) bool {
+	return slices.Contains(s.Config.Admins, user.Name)
+}
+
+// canManageFeed reports whether the user owns the feed or is an admin. Feeds
+// without an owner can only be managed by admins.
+func canManageFeed(s *State, user database.User, feed database.Feed) bool {
+	return (feed.UserID.Valid && feed.UserID.UUID == user.ID) || isAdmin(s, user)
+}
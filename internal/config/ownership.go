@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// systemOwner is shown for feeds without an owner, which only admins can
+// manage.
+const systemOwner = "system"
+
+const defaultOrphanGrace = 30 * 24 * time.Hour
+
+// transferOwnedFeeds hands every feed owned by user to its earliest other
+// follower, or to the system when nobody else follows it.
+func transferOwnedFeeds(q *database.Queries, user database.User) error {
+	feeds, err := q.ListFeedsForOwner(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("error listing feeds of user %s: %w", user.Name, err)
+	}
+	for _, feed := range feeds {
+		var owner uuid.NullUUID
+		ownerName := systemOwner
+		next, err := q.GetEarliestOtherFollower(context.Background(), database.GetEarliestOtherFollowerParams{
+			FeedID: feed.ID,
+			UserID: user.ID,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("error finding a new owner for feed %s: %w", feed.Url, err)
+		}
+		if err == nil {
+			owner = uuid.NullUUID{UUID: next.ID, Valid: true}
+			ownerName = next.Name
+		}
+		err = q.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+			UserID:    owner,
+			UpdatedAt: time.Now(),
+			ID:        feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error transferring feed %s: %w", feed.Url, err)
+		}
+		fmt.Printf("Transferred feed %s to %s\n", feed.Name, ownerName)
+	}
+	return nil
+}
+
+func handlerUsersDelete(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("users delete requires a username argument")
+	}
+	target, err := s.DB.GetUserByName(context.Background(), cmd.Args[0])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user %s does not exist", cmd.Args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching user: %w", err)
+	}
+	if target.ID != user.ID && !isAdmin(s, user) {
+		return fmt.Errorf("only admins can delete other users")
+	}
+	err = s.inTx(func(q *database.Queries) error {
+		if err := transferOwnedFeeds(q, target); err != nil {
+			return err
+		}
+		if err := q.DeleteUser(context.Background(), target.ID); err != nil {
+			return fmt.Errorf("error deleting user %s: %w", target.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted user %s along with their follows, rules and kept posts\n", target.Name)
+	if target.Name == s.Config.CurrentUserName {
+		if err := s.Config.SetUser(""); err != nil {
+			return fmt.Errorf("error logging out: %w", err)
+		}
+		fmt.Println("Logged out")
+	}
+	return nil
+}
+
+func handlerFeedTransfer(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return fmt.Errorf("feed transfer requires a feed url and a username")
+	}
+	feed, err := lookupFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's owner or an admin can transfer it")
+	}
+	target, err := s.DB.GetUserByName(context.Background(), cmd.Args[1])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user %s does not exist", cmd.Args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching user: %w", err)
+	}
+	err = s.DB.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+		UserID:    uuid.NullUUID{UUID: target.ID, Valid: true},
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error transferring feed %s: %w", feed.Url, err)
+	}
+	fmt.Printf("Transferred feed %s to %s\n", feed.Name, target.Name)
+	return nil
+}
+
+func orphanGrace(s *State) (time.Duration, error) {
+	if s.Config.OrphanGrace == "" {
+		return defaultOrphanGrace, nil
+	}
+	grace, err := time.ParseDuration(s.Config.OrphanGrace)
+	if err != nil {
+		return 0, fmt.Errorf("invalid orphan_grace %q: %w", s.Config.OrphanGrace, err)
+	}
+	return grace, nil
+}
+
+// collectOrphanedFeeds notes when feeds lose their last follower and removes
+// those that have had none for longer than the grace period and no kept
+// posts, returning how many were removed (or would be removed when dryRun
+// is set).
+func collectOrphanedFeeds(s *State, dryRun bool) (int, error) {
+	grace, err := orphanGrace(s)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if !dryRun {
+		if _, err := s.DB.ClearAdoptedFeeds(context.Background()); err != nil {
+			return 0, fmt.Errorf("error updating followed feeds: %w", err)
+		}
+		if _, err := s.DB.MarkOrphanedFeeds(context.Background(), now); err != nil {
+			return 0, fmt.Errorf("error marking feeds without followers: %w", err)
+		}
+	}
+	feeds, err := s.DB.ListOrphanedFeeds(context.Background(), now.Add(-grace))
+	if err != nil {
+		return 0, fmt.Errorf("error listing feeds without followers: %w", err)
+	}
+	for _, feed := range feeds {
+		posts, err := s.DB.CountFeedPosts(context.Background(), feed.ID)
+		if err != nil {
+			return 0, fmt.Errorf("error counting posts of feed %s: %w", feed.Url, err)
+		}
+		if !dryRun {
+			if err := s.DB.DeleteFeed(context.Background(), feed.ID); err != nil {
+				return 0, fmt.Errorf("error deleting feed %s: %w", feed.Url, err)
+			}
+		}
+		fmt.Printf("%s (%s): no followers since %s, %d posts\n",
+			feed.Name,
+			feed.Url,
+			feed.OrphanedAt.Time.Format("Jan 2, 2006"),
+			posts,
+		)
+	}
+	return len(feeds), nil
+}
+
+func handlerFeedsGC(s *State, cmd Command) error {
+	fs := newFlagSet(cmd.Name)
+	dryRun := fs.Bool("dry-run", false, "only report what would be removed")
+	if _, err := parseFlags(fs, cmd.Args); err != nil {
+		return err
+	}
+	total, err := collectOrphanedFeeds(s, *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("Would remove %d feeds without followers\n", total)
+	} else {
+		fmt.Printf("Removed %d feeds without followers\n", total)
+		if _, err := sweepImageCache(s); err != nil {
+			return fmt.Errorf("error cleaning image cache: %w", err)
+		}
+	}
+	return nil
+}
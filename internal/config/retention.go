@@ -0,0 +1,241 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+)
+
+// retentionPolicy is the effective retention for a single feed. A zero
+// maxPosts or maxAge means that limit is not applied.
+type retentionPolicy struct {
+	maxPosts int
+	maxAge   time.Duration
+}
+
+func (p retentionPolicy) enabled() bool {
+	return p.maxPosts > 0 || p.maxAge > 0
+}
+
+func (p retentionPolicy) String() string {
+	if !p.enabled() {
+		return "keep everything"
+	}
+	desc := ""
+	if p.maxPosts > 0 {
+		desc = fmt.Sprintf("keep last %d posts", p.maxPosts)
+	}
+	if p.maxAge > 0 {
+		if desc != "" {
+			desc += ", "
+		}
+		desc += fmt.Sprintf("drop posts older than %s", p.maxAge)
+	}
+	return desc
+}
+
+// resolveRetention merges a feed's overrides on top of the global policy.
+func resolveRetention(global RetentionConfig, maxPosts sql.NullInt32, maxAgeSeconds sql.NullInt64) (retentionPolicy, error) {
+	policy := retentionPolicy{maxPosts: global.MaxPosts}
+	if global.MaxAge != "" {
+		maxAge, err := time.ParseDuration(global.MaxAge)
+		if err != nil {
+			return retentionPolicy{}, fmt.Errorf("invalid retention max_age %q in config: %w", global.MaxAge, err)
+		}
+		policy.maxAge = maxAge
+	}
+	if maxPosts.Valid {
+		policy.maxPosts = int(maxPosts.Int32)
+	}
+	if maxAgeSeconds.Valid {
+		policy.maxAge = time.Duration(maxAgeSeconds.Int64) * time.Second
+	}
+	return policy, nil
+}
+
+// prunePosts applies the retention policy of every feed, returning the number
+// of posts removed (or that would be removed when dryRun is set).
+func prunePosts(s *State, dryRun bool) (int64, error) {
+	feeds, err := s.DB.ListFeedRetentionPolicies(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("error listing retention policies: %w", err)
+	}
+	var total int64
+	for _, feed := range feeds {
+		policy, err := resolveRetention(s.Config.Retention, feed.MaxPosts, feed.MaxAgeSeconds)
+		if err != nil {
+			return total, err
+		}
+		if !policy.enabled() {
+			continue
+		}
+		var publishedBefore sql.NullTime
+		if policy.maxAge > 0 {
+			publishedBefore = sql.NullTime{Time: time.Now().Add(-policy.maxAge), Valid: true}
+		}
+		var count int64
+		if dryRun {
+			count, err = s.DB.CountPrunablePosts(context.Background(), database.CountPrunablePostsParams{
+				FeedID:          feed.ID,
+				PublishedBefore: publishedBefore,
+				KeepLatest:      int32(policy.maxPosts),
+			})
+		} else {
+			count, err = s.DB.PrunePosts(context.Background(), database.PrunePostsParams{
+				FeedID:          feed.ID,
+				PublishedBefore: publishedBefore,
+				KeepLatest:      int32(policy.maxPosts),
+			})
+		}
+		if err != nil {
+			return total, fmt.Errorf("error pruning posts for feed %s: %w", feed.Url, err)
+		}
+		if count > 0 {
+			fmt.Printf("%s: %d posts (%s)\n", feed.Name, count, policy)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func handlerPrune(s *State, cmd Command) error {
+	fs := newFlagSet(cmd.Name)
+	dryRun := fs.Bool("dry-run", false, "only report what would be removed")
+	if _, err := parseFlags(fs, cmd.Args); err != nil {
+		return err
+	}
+	total, err := prunePosts(s, *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("Would prune %d posts\n", total)
+	} else {
+		fmt.Printf("Pruned %d posts\n", total)
+		if _, err := sweepImageCache(s); err != nil {
+			return fmt.Errorf("error cleaning image cache: %w", err)
+		}
+	}
+	return nil
+}
+
+func handlerRetention(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	keep := fs.Int("keep", 0, "keep only the last N posts (0 for no limit)")
+	maxAge := fs.Duration("max-age", 0, "drop posts older than this duration (0 for no limit)")
+	clearOverride := fs.Bool("clear", false, "remove the feed's override and use the global policy")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("retention command requires a feed url argument")
+	}
+	feedURL := args[0]
+	feed, err := lookupFeed(s, feedURL)
+	if err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	// Posts are shared, so pruning one feed harder affects all its followers.
+	if (*clearOverride || set["keep"] || set["max-age"]) && !canManageFeed(s, user, feed) {
+		return fmt.Errorf("only the feed's creator or an admin can change its retention")
+	}
+
+	switch {
+	case *clearOverride:
+		if err := s.DB.DeleteFeedRetention(context.Background(), feed.ID); err != nil {
+			return fmt.Errorf("error clearing retention for feed %s: %w", feedURL, err)
+		}
+		fmt.Printf("Cleared retention override for feed %s\n", feedURL)
+	case set["keep"] || set["max-age"]:
+		current, err := s.DB.GetFeedRetention(context.Background(), feed.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("error fetching retention for feed %s: %w", feedURL, err)
+		}
+		maxPosts := current.MaxPosts
+		if set["keep"] {
+			if *keep < 0 || *keep > math.MaxInt32 {
+				return fmt.Errorf("keep must be between 0 and %d", math.MaxInt32)
+			}
+			maxPosts = sql.NullInt32{Int32: int32(*keep), Valid: true}
+		}
+		maxAgeSeconds := current.MaxAgeSeconds
+		if set["max-age"] {
+			if *maxAge < 0 {
+				return fmt.Errorf("max-age must not be negative")
+			}
+			maxAgeSeconds = sql.NullInt64{Int64: int64(maxAge.Seconds()), Valid: true}
+		}
+		_, err = s.DB.UpsertFeedRetention(context.Background(), database.UpsertFeedRetentionParams{
+			FeedID:        feed.ID,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+			MaxPosts:      maxPosts,
+			MaxAgeSeconds: maxAgeSeconds,
+		})
+		if err != nil {
+			return fmt.Errorf("error setting retention for feed %s: %w", feedURL, err)
+		}
+		fmt.Printf("Updated retention for feed %s\n", feedURL)
+	}
+
+	current, err := s.DB.GetFeedRetention(context.Background(), feed.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error fetching retention for feed %s: %w", feedURL, err)
+	}
+	policy, err := resolveRetention(s.Config.Retention, current.MaxPosts, current.MaxAgeSeconds)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Retention for %s: %s\n", feed.Name, policy)
+	return nil
+}
+
+func handlerKeep(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("keep command requires a post id or url argument")
+	}
+	post, err := lookupPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	err = s.DB.KeepPost(context.Background(), database.KeepPostParams{
+		UserID:    user.ID,
+		PostID:    post.ID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("error keeping post %s: %w", post.Url, err)
+	}
+	fmt.Printf("Keeping post %s\n", post.Title)
+	return nil
+}
+
+func handlerUnkeep(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("unkeep command requires a post id or url argument")
+	}
+	post, err := lookupPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	removed, err := s.DB.UnkeepPost(context.Background(), database.UnkeepPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error unkeeping post %s: %w", post.Url, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("post %s was not kept", post.Title)
+	}
+	fmt.Printf("No longer keeping post %s\n", post.Title)
+	return nil
+}
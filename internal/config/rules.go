@@ -0,0 +1,211 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	ruleActionMute      = "mute"
+	ruleActionHighlight = "highlight"
+)
+
+// postRule is a compiled mute or highlight rule evaluated in the browse pipeline.
+type postRule struct {
+	action string
+	feedID uuid.NullUUID
+	field  string
+	match  func(string) bool
+}
+
+func compileRule(matchType, pattern string) (func(string) bool, error) {
+	switch matchType {
+	case "contains":
+		needle := strings.ToLower(pattern)
+		return func(text string) bool {
+			return strings.Contains(strings.ToLower(text), needle)
+		}, nil
+	case "regex":
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("unknown match type %s", matchType)
+	}
+}
+
+func loadRules(s *State, user database.User) ([]postRule, error) {
+	rows, err := s.DB.ListRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching rules: %w", err)
+	}
+	rules := make([]postRule, 0, len(rows))
+	for _, row := range rows {
+		match, err := compileRule(row.MatchType, row.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", row.ID, err)
+		}
+		rules = append(rules, postRule{
+			action: row.Action,
+			feedID: row.FeedID,
+			field:  row.Field,
+			match:  match,
+		})
+	}
+	return rules, nil
+}
+
+// evaluateRules reports whether a post is muted or highlighted. Mute wins when
+// both kinds of rule match.
+func evaluateRules(rules []postRule, feedID uuid.UUID, title, description string) (muted, highlighted bool) {
+	for _, rule := range rules {
+		if rule.feedID.Valid && rule.feedID.UUID != feedID {
+			continue
+		}
+		matched := false
+		switch rule.field {
+		case "title":
+			matched = rule.match(title)
+		case "description":
+			matched = rule.match(description)
+		default:
+			matched = rule.match(title) || rule.match(description)
+		}
+		if !matched {
+			continue
+		}
+		switch rule.action {
+		case ruleActionMute:
+			muted = true
+		case ruleActionHighlight:
+			highlighted = true
+		}
+	}
+	return muted, highlighted && !muted
+}
+
+func handlerRule(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("rule command requires a subcommand: add, list or rm")
+	}
+	sub := Command{Name: cmd.Name + " " + cmd.Args[0], Args: cmd.Args[1:]}
+	switch cmd.Args[0] {
+	case "add":
+		return handlerRuleAdd(s, sub, user)
+	case "list":
+		return handlerRuleList(s, sub, user)
+	case "rm":
+		return handlerRuleRemove(s, sub, user)
+	default:
+		return fmt.Errorf("unknown rule subcommand %s", cmd.Args[0])
+	}
+}
+
+func handlerRuleAdd(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	regex := fs.Bool("regex", false, "treat the pattern as a regular expression")
+	field := fs.String("field", "any", "field to match: title, description or any")
+	feedURL := fs.String("feed", "", "only apply the rule to this feed")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("rule add requires an action (mute or highlight) and a pattern")
+	}
+	action, pattern := args[0], args[1]
+	if action != ruleActionMute && action != ruleActionHighlight {
+		return fmt.Errorf("unknown rule action %s, expected mute or highlight", action)
+	}
+	if *field != "title" && *field != "description" && *field != "any" {
+		return fmt.Errorf("unknown rule field %s, expected title, description or any", *field)
+	}
+	matchType := "contains"
+	if *regex {
+		matchType = "regex"
+	}
+	if _, err := compileRule(matchType, pattern); err != nil {
+		return err
+	}
+	var feedID uuid.NullUUID
+	if *feedURL != "" {
+		feed, err := lookupFeed(s, *feedURL)
+		if err != nil {
+			return err
+		}
+		feedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	rule, err := s.DB.CreateRule(context.Background(), database.CreateRuleParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feedID,
+		Action:    action,
+		Field:     *field,
+		MatchType: matchType,
+		Pattern:   pattern,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating rule: %w", err)
+	}
+	fmt.Printf("Added rule %s\n", rule.ID)
+	return nil
+}
+
+func handlerRuleList(s *State, _ Command, user database.User) error {
+	rules, err := s.DB.ListRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching rules: %w", err)
+	}
+	if len(rules) == 0 {
+		fmt.Println("No rules found")
+		return nil
+	}
+	fmt.Println("Rules:")
+	for _, rule := range rules {
+		scope := "all feeds"
+		if rule.FeedUrl.Valid {
+			scope = rule.FeedUrl.String
+		}
+		fmt.Printf("- %s | %s %s %s %q | %s\n",
+			rule.ID,
+			rule.Action,
+			rule.Field,
+			rule.MatchType,
+			rule.Pattern,
+			scope,
+		)
+	}
+	return nil
+}
+
+func handlerRuleRemove(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("rule rm requires a rule id argument")
+	}
+	id, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid rule id %s", cmd.Args[0])
+	}
+	removed, err := s.DB.DeleteRule(context.Background(), database.DeleteRuleParams{
+		ID:     id,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error removing rule: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("rule %s not found", id)
+	}
+	fmt.Printf("Removed rule %s\n", id)
+	return nil
+}
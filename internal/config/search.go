@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// printAuthorsAndTags prints the authors and tags recorded for a post, if any.
+func printAuthorsAndTags(s *State, postID uuid.UUID) error {
+	authors, err := s.DB.ListAuthorsForPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("error fetching authors: %w", err)
+	}
+	if len(authors) > 0 {
+		fmt.Printf("By: %s\n", strings.Join(authors, ", "))
+	}
+	tags, err := s.DB.ListTagsForPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("error fetching tags: %w", err)
+	}
+	if len(tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(tags, ", "))
+	}
+	return nil
+}
+
+// likeEscaper escapes the ILIKE wildcards in a search term, so that "100%"
+// and "snake_case" match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func handlerSearch(s *State, cmd Command, user database.User) error {
+	fs := newFlagSet(cmd.Name)
+	author := fs.String("author", "", "only match posts by this author")
+	tag := fs.String("tag", "", "only match posts with this tag or category")
+	limit := fs.Int("limit", 20, "maximum number of posts to list")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	query := strings.Join(args, " ")
+	if query == "" && *author == "" && *tag == "" {
+		return fmt.Errorf("search command requires a search term, --author or --tag")
+	}
+	posts, err := s.DB.SearchPostsForUser(context.Background(), database.SearchPostsForUserParams{
+		UserID: user.ID,
+		Query:  likeEscaper.Replace(query),
+		Author: sql.NullString{String: *author, Valid: *author != ""},
+		Tag:    sql.NullString{String: *tag, Valid: *tag != ""},
+		Limit:  int32(*limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error searching posts: %w", err)
+	}
+	if len(posts) == 0 {
+		fmt.Println("No matching posts found")
+		return nil
+	}
+	fmt.Printf("Found %d matching posts:\n", len(posts))
+	for _, post := range posts {
+		fmt.Printf("%s from %s\n", formatPublished(post.PublishedAt, post.PublishedAtEstimated), post.FeedName)
+		fmt.Printf("--- %s ---\n", post.Title)
+		if err := printAuthorsAndTags(s, post.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Link: %s\n", post.Url)
+		fmt.Printf("ID: %s\n", post.ID)
+		fmt.Println("=====================================")
+	}
+	return nil
+}
@@ -1,8 +1,34 @@
 package config
 
-import "github.com/JonahLargen/BlogAggregator/internal/database"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+)
 
 type State struct {
-	Config *Config
-	DB     *database.Queries
+	Config  *Config
+	DB      *database.Queries
+	Conn    *sql.DB
+	Fetcher *rss.Fetcher
+}
+
+// inTx runs fn with queries bound to a single transaction, which is committed
+// if fn succeeds and rolled back otherwise.
+func (s *State) inTx(fn func(q *database.Queries) error) error {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := fn(s.DB.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JonahLargen/BlogAggregator/internal/database"
+	"github.com/JonahLargen/BlogAggregator/internal/rss"
+	"github.com/google/uuid"
+)
+
+const (
+	// fetchHistory is how long fetch records are kept for feed stats.
+	fetchHistory = 90 * 24 * time.Hour
+	// statsWeeks is the window posts per week are averaged over.
+	statsWeeks = 4
+	// statusHistory is how many recent fetches feed stats lists.
+	statusHistory = 20
+)
+
+const statsTimeFormat = "Jan 2, 2006 15:04"
+
+// recordFetch stores the outcome of one fetch of a feed for feed stats and
+// drops records older than fetchHistory.
+func recordFetch(s *State, feedID uuid.UUID, started time.Time, feed *rss.RSSFeed, fetchErr error) error {
+	params := database.CreateFeedFetchParams{
+		ID:         uuid.New(),
+		FeedID:     feedID,
+		FetchedAt:  started,
+		DurationMs: int32(time.Since(started).Milliseconds()),
+	}
+	var statusErr *rss.StatusError
+	switch {
+	case fetchErr == nil:
+		params.StatusCode = sql.NullInt32{Int32: http.StatusOK, Valid: true}
+		params.Bytes = feed.Bytes
+	case errors.As(fetchErr, &statusErr):
+		params.StatusCode = sql.NullInt32{Int32: int32(statusErr.StatusCode), Valid: true}
+	}
+	if fetchErr != nil {
+		params.Error = sql.NullString{String: fetchErr.Error(), Valid: true}
+	}
+	if err := s.DB.CreateFeedFetch(context.Background(), params); err != nil {
+		return fmt.Errorf("error recording fetch: %w", err)
+	}
+	err := s.DB.PruneFeedFetches(context.Background(), database.PruneFeedFetchesParams{
+		FeedID:    feedID,
+		FetchedAt: started.Add(-fetchHistory),
+	})
+	if err != nil {
+		return fmt.Errorf("error pruning fetch history: %w", err)
+	}
+	return nil
+}
+
+func handlerFeedStats(s *State, cmd Command, _ database.User) error {
+	if len(cmd.Args) > 0 {
+		feed, err := lookupFeed(s, cmd.Args[0])
+		if err != nil {
+			return err
+		}
+		return printFeedStats(s, feed)
+	}
+	feeds, err := s.DB.ListFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("error listing feeds: %w", err)
+	}
+	if len(feeds) == 0 {
+		fmt.Println("No feeds found")
+		return nil
+	}
+	for _, feed := range feeds {
+		if err := printFeedSummary(s, feed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printFeedSummary prints one line about the health of a feed.
+func printFeedSummary(s *State, feed database.ListFeedsRow) error {
+	followers, err := s.DB.CountFeedFollows(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("error counting followers of feed %s: %w", feed.Url, err)
+	}
+	fetches, err := s.DB.ListFeedFetches(context.Background(), database.ListFeedFetchesParams{
+		FeedID: feed.ID,
+		Limit:  1,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching history of feed %s: %w", feed.Url, err)
+	}
+	status := "never fetched"
+	if len(fetches) > 0 {
+		status = fmt.Sprintf("last fetch %s on %s", fetchStatus(fetches[0]), fetches[0].FetchedAt.Format(statsTimeFormat))
+	}
+	if feed.RetiredAt.Valid {
+		status = "retired, " + status
+	}
+	fmt.Printf("%s (%s): %s, %d followers\n", feed.Name, feed.Url, status, followers)
+	return nil
+}
+
+// printFeedStats prints the fetch history and posting activity of a feed.
+func printFeedStats(s *State, feed database.Feed) error {
+	ctx := context.Background()
+	followers, err := s.DB.CountFeedFollows(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("error counting followers of feed %s: %w", feed.Url, err)
+	}
+	totals, err := s.DB.GetFeedFetchTotals(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching history of feed %s: %w", feed.Url, err)
+	}
+	lastSuccess, err := s.DB.GetLastSuccessfulFeedFetch(ctx, feed.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error fetching history of feed %s: %w", feed.Url, err)
+	}
+	lastFailure, err := s.DB.GetLastFailedFeedFetch(ctx, feed.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error fetching history of feed %s: %w", feed.Url, err)
+	}
+	recent, err := s.DB.ListFeedFetches(ctx, database.ListFeedFetchesParams{
+		FeedID: feed.ID,
+		Limit:  statusHistory,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching history of feed %s: %w", feed.Url, err)
+	}
+	since := time.Now().AddDate(0, 0, -7*statsWeeks)
+	recentPosts, err := s.DB.CountFeedPostsSince(ctx, database.CountFeedPostsSinceParams{
+		FeedID:      feed.ID,
+		PublishedAt: sql.NullTime{Time: since, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("error counting posts of feed %s: %w", feed.Url, err)
+	}
+	newest, err := s.DB.GetNewestPostForFeed(ctx, feed.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error fetching newest post of feed %s: %w", feed.Url, err)
+	}
+	aliases, err := s.DB.ListFeedAliases(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching aliases of feed %s: %w", feed.Url, err)
+	}
+
+	fmt.Printf("Feed: %s (%s)\n", feed.Name, feed.Url)
+	if feed.RetiredAt.Valid {
+		fmt.Printf("Retired on %s; no longer fetched\n", feed.RetiredAt.Time.Format("Jan 2, 2006"))
+	}
+	for _, alias := range aliases {
+		fmt.Printf("Formerly: %s (since %s)\n", alias.Url, alias.CreatedAt.Format("Jan 2, 2006"))
+	}
+	fmt.Printf("Followers: %d\n", followers)
+	if lastSuccess.ID != uuid.Nil {
+		fmt.Printf("Last successful fetch: %s\n", lastSuccess.FetchedAt.Format(statsTimeFormat))
+	} else {
+		fmt.Println("Last successful fetch: never")
+	}
+	if lastFailure.ID != uuid.Nil {
+		fmt.Printf("Last error: %s on %s\n", lastFailure.Error.String, lastFailure.FetchedAt.Format(statsTimeFormat))
+	} else {
+		fmt.Println("Last error: none")
+	}
+	if totals.Fetches > 0 {
+		latency := time.Duration(totals.AvgDurationMs * float64(time.Millisecond)).Round(time.Millisecond)
+		days := int(fetchHistory.Hours() / 24)
+		fmt.Printf("Average fetch latency: %s (%d fetches in the last %d days)\n", latency, totals.Fetches, days)
+		fmt.Printf("Bytes transferred: %s (last %d days)\n", formatBytes(totals.TotalBytes), days)
+	}
+	if len(recent) > 0 {
+		statuses := make([]string, len(recent))
+		for i, fetch := range recent {
+			statuses[i] = fetchStatus(fetch)
+		}
+		fmt.Printf("Recent statuses (newest first): %s\n", strings.Join(statuses, " "))
+	}
+	fmt.Printf("Posts per week: %.1f (last %d weeks)\n", float64(recentPosts)/statsWeeks, statsWeeks)
+	if newest.PublishedAt.Valid {
+		fmt.Printf("Newest post: %s\n", formatPublished(newest.PublishedAt, newest.PublishedAtEstimated))
+	} else {
+		fmt.Println("Newest post: none")
+	}
+	return nil
+}
+
+// fetchStatus is the HTTP status of a fetch, or "error" when no response
+// was received.
+func fetchStatus(fetch database.FeedFetch) string {
+	if !fetch.StatusCode.Valid {
+		return "error"
+	}
+	return strconv.Itoa(int(fetch.StatusCode.Int32))
+}
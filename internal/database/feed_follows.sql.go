@@ -7,11 +7,25 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countFeedFollows = `-- name: CountFeedFollows :one
+SELECT COUNT(*)
+FROM feed_follows
+WHERE feed_id = $1
+`
+
+func (q *Queries) CountFeedFollows(ctx context.Context, feedID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedFollows, feedID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeedFollow = `-- name: CreateFeedFollow :one
 WITH inserted_feed_follow AS (
     INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
@@ -100,28 +114,66 @@ func (q *Queries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowPara
 	return i, err
 }
 
+const getEarliestOtherFollower = `-- name: GetEarliestOtherFollower :one
+SELECT u.id, u.created_at, u.updated_at, u.name
+FROM feed_follows ff
+JOIN users u ON ff.user_id = u.id
+WHERE ff.feed_id = $1 AND ff.user_id <> $2
+ORDER BY ff.created_at
+LIMIT 1
+`
+
+type GetEarliestOtherFollowerParams struct {
+	FeedID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetEarliestOtherFollower(ctx context.Context, arg GetEarliestOtherFollowerParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getEarliestOtherFollower, arg.FeedID, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT
-    feeds.name AS feed_name
+    feeds.name AS feed_name,
+    feeds.url AS feed_url,
+    feeds.retired_at
 FROM feed_follows feed_follows
 JOIN feeds ON feed_follows.feed_id = feeds.id
 WHERE feed_follows.user_id = $1
 ORDER BY feeds.name
 `
 
-func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]string, error) {
+type GetFeedFollowsForUserRow struct {
+	FeedName  string
+	FeedUrl   string
+	RetiredAt sql.NullTime
+}
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
 	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetFeedFollowsForUserRow
 	for rows.Next() {
-		var feed_name string
-		if err := rows.Scan(&feed_name); err != nil {
+		var i GetFeedFollowsForUserRow
+		if err := rows.Scan(
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.RetiredAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, feed_name)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -131,3 +183,25 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	}
 	return items, nil
 }
+
+const moveFeedFollows = `-- name: MoveFeedFollows :execrows
+UPDATE feed_follows
+SET feed_id = $1, updated_at = NOW()
+WHERE feed_id = $2
+AND user_id NOT IN (
+    SELECT user_id FROM feed_follows WHERE feed_id = $1
+)
+`
+
+type MoveFeedFollowsParams struct {
+	KeeperID    uuid.UUID
+	DuplicateID uuid.UUID
+}
+
+func (q *Queries) MoveFeedFollows(ctx context.Context, arg MoveFeedFollowsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveFeedFollows, arg.KeeperID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
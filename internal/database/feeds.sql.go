@@ -13,26 +13,43 @@ import (
 	"github.com/google/uuid"
 )
 
+const clearAdoptedFeeds = `-- name: ClearAdoptedFeeds :execrows
+UPDATE feeds f
+SET orphaned_at = NULL
+WHERE f.orphaned_at IS NOT NULL
+AND EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id)
+`
+
+func (q *Queries) ClearAdoptedFeeds(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearAdoptedFeeds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, canonical_url)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, parse_recovered, next_fetch_at, retired_at, canonical_url, orphaned_at
 `
 
 type CreateFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	Url          string
+	UserID       uuid.NullUUID
+	CanonicalUrl string
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -43,7 +60,70 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.CanonicalUrl,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ParseRecovered,
+		&i.NextFetchAt,
+		&i.RetiredAt,
+		&i.CanonicalUrl,
+		&i.OrphanedAt,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const getFeedByCanonicalUrl = `-- name: GetFeedByCanonicalUrl :one
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.parse_recovered, f.next_fetch_at, f.retired_at, f.canonical_url, f.orphaned_at
+FROM feeds f
+WHERE f.canonical_url = $1
+`
+
+func (q *Queries) GetFeedByCanonicalUrl(ctx context.Context, canonicalUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByCanonicalUrl, canonicalUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ParseRecovered,
+		&i.NextFetchAt,
+		&i.RetiredAt,
+		&i.CanonicalUrl,
+		&i.OrphanedAt,
 	)
+	return i, err
+}
+
+const getFeedByID = `-- name: GetFeedByID :one
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.parse_recovered, f.next_fetch_at, f.retired_at, f.canonical_url, f.orphaned_at
+FROM feeds f
+WHERE f.id = $1
+`
+
+func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByID, id)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -53,12 +133,17 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ParseRecovered,
+		&i.NextFetchAt,
+		&i.RetiredAt,
+		&i.CanonicalUrl,
+		&i.OrphanedAt,
 	)
 	return i, err
 }
 
 const getFeedByUrl = `-- name: GetFeedByUrl :one
-SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.parse_recovered, f.next_fetch_at, f.retired_at, f.canonical_url, f.orphaned_at
 FROM feeds f
 WHERE f.url = $1
 LIMIT 1
@@ -75,19 +160,26 @@ func (q *Queries) GetFeedByUrl(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ParseRecovered,
+		&i.NextFetchAt,
+		&i.RetiredAt,
+		&i.CanonicalUrl,
+		&i.OrphanedAt,
 	)
 	return i, err
 }
 
 const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, parse_recovered, next_fetch_at, retired_at, canonical_url, orphaned_at
 FROM feeds
+WHERE retired_at IS NULL
+AND (next_fetch_at IS NULL OR next_fetch_at <= $1::timestamp)
 ORDER BY last_fetched_at NULLS FIRST, created_at ASC
 LIMIT 1
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
+func (q *Queries) GetNextFeedToFetch(ctx context.Context, now time.Time) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getNextFeedToFetch, now)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -97,26 +189,36 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ParseRecovered,
+		&i.NextFetchAt,
+		&i.RetiredAt,
+		&i.CanonicalUrl,
+		&i.OrphanedAt,
 	)
 	return i, err
 }
 
 const listFeeds = `-- name: ListFeeds :many
-SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, u.name as "user_name" 
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.parse_recovered, f.next_fetch_at, f.retired_at, f.canonical_url, f.orphaned_at, u.name as "user_name" 
 FROM feeds f
-join users u on f.user_id = u.id
+left join users u on f.user_id = u.id
 ORDER BY f.created_at DESC
 `
 
 type ListFeedsRow struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
-	UserName      string
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Name           string
+	Url            string
+	UserID         uuid.NullUUID
+	LastFetchedAt  sql.NullTime
+	ParseRecovered bool
+	NextFetchAt    sql.NullTime
+	RetiredAt      sql.NullTime
+	CanonicalUrl   string
+	OrphanedAt     sql.NullTime
+	UserName       sql.NullString
 }
 
 func (q *Queries) ListFeeds(ctx context.Context) ([]ListFeedsRow, error) {
@@ -136,6 +238,11 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]ListFeedsRow, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.ParseRecovered,
+			&i.NextFetchAt,
+			&i.RetiredAt,
+			&i.CanonicalUrl,
+			&i.OrphanedAt,
 			&i.UserName,
 		); err != nil {
 			return nil, err
@@ -151,6 +258,98 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]ListFeedsRow, error) {
 	return items, nil
 }
 
+const listFeedsForOwner = `-- name: ListFeedsForOwner :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, parse_recovered, next_fetch_at, retired_at, canonical_url, orphaned_at
+FROM feeds
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListFeedsForOwner(ctx context.Context, userID uuid.NullUUID) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listFeedsForOwner, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ParseRecovered,
+			&i.NextFetchAt,
+			&i.RetiredAt,
+			&i.CanonicalUrl,
+			&i.OrphanedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanedFeeds = `-- name: ListOrphanedFeeds :many
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.parse_recovered, f.next_fetch_at, f.retired_at, f.canonical_url, f.orphaned_at
+FROM feeds f
+WHERE f.orphaned_at <= $1::timestamp
+AND NOT EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id)
+AND NOT EXISTS (
+    SELECT 1 FROM posts p
+    JOIN post_keeps k ON k.post_id = p.id
+    WHERE p.feed_id = f.id
+)
+ORDER BY f.orphaned_at
+`
+
+func (q *Queries) ListOrphanedFeeds(ctx context.Context, orphanedBefore time.Time) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listOrphanedFeeds, orphanedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ParseRecovered,
+			&i.NextFetchAt,
+			&i.RetiredAt,
+			&i.CanonicalUrl,
+			&i.OrphanedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markFeedFetched = `-- name: MarkFeedFetched :exec
 UPDATE feeds
 SET last_fetched_at = $1
@@ -158,11 +357,149 @@ WHERE id = $2
 `
 
 type MarkFeedFetchedParams struct {
-	LastFetchedAt sql.NullTime
-	ID            uuid.UUID
+	LastFetchedAt  sql.NullTime
+	ParseRecovered bool
+	ID             uuid.UUID
 }
 
 func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
 	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.LastFetchedAt, arg.ID)
 	return err
 }
+
+const markOrphanedFeeds = `-- name: MarkOrphanedFeeds :execrows
+UPDATE feeds f
+SET orphaned_at = $1::timestamp
+WHERE f.orphaned_at IS NULL
+AND NOT EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id)
+`
+
+func (q *Queries) MarkOrphanedFeeds(ctx context.Context, now time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markOrphanedFeeds, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameFeed = `-- name: RenameFeed :exec
+UPDATE feeds
+SET name = $1, updated_at = $2
+WHERE id = $3
+`
+
+type RenameFeedParams struct {
+	Name      string
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) error {
+	_, err := q.db.ExecContext(ctx, renameFeed, arg.Name, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedCanonicalUrl = `-- name: SetFeedCanonicalUrl :exec
+UPDATE feeds
+SET canonical_url = $1, updated_at = $2
+WHERE id = $3
+`
+
+type SetFeedCanonicalUrlParams struct {
+	CanonicalUrl string
+	UpdatedAt    time.Time
+	ID           uuid.UUID
+}
+
+func (q *Queries) SetFeedCanonicalUrl(ctx context.Context, arg SetFeedCanonicalUrlParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedCanonicalUrl, arg.CanonicalUrl, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedNextFetchAt = `-- name: SetFeedNextFetchAt :exec
+UPDATE feeds
+SET next_fetch_at = $1
+WHERE id = $2
+`
+
+type SetFeedNextFetchAtParams struct {
+	NextFetchAt sql.NullTime
+	ID          uuid.UUID
+}
+
+func (q *Queries) SetFeedNextFetchAt(ctx context.Context, arg SetFeedNextFetchAtParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNextFetchAt, arg.NextFetchAt, arg.ID)
+	return err
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :exec
+UPDATE feeds
+SET user_id = $1, updated_at = $2
+WHERE id = $3
+`
+
+type SetFeedOwnerParams struct {
+	UserID    uuid.NullUUID
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedOwner, arg.UserID, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedParseRecovered = `-- name: SetFeedParseRecovered :exec
+UPDATE feeds
+SET parse_recovered = $1
+WHERE id = $2
+`
+
+type SetFeedParseRecoveredParams struct {
+	ParseRecovered bool
+	ID             uuid.UUID
+}
+
+func (q *Queries) SetFeedParseRecovered(ctx context.Context, arg SetFeedParseRecoveredParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedParseRecovered, arg.ParseRecovered, arg.ID)
+	return err
+}
+
+const setFeedRetiredAt = `-- name: SetFeedRetiredAt :exec
+UPDATE feeds
+SET retired_at = $1, next_fetch_at = NULL
+WHERE id = $2
+`
+
+type SetFeedRetiredAtParams struct {
+	RetiredAt sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) SetFeedRetiredAt(ctx context.Context, arg SetFeedRetiredAtParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetiredAt, arg.RetiredAt, arg.ID)
+	return err
+}
+
+const updateFeedUrl = `-- name: UpdateFeedUrl :exec
+UPDATE feeds
+SET url = $1, canonical_url = $2, updated_at = $3
+WHERE id = $4
+`
+
+type UpdateFeedUrlParams struct {
+	Url          string
+	CanonicalUrl string
+	UpdatedAt    time.Time
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateFeedUrl(ctx context.Context, arg UpdateFeedUrlParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedUrl,
+		arg.Url,
+		arg.CanonicalUrl,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
@@ -12,32 +12,140 @@ import (
 )
 
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Name           string
+	Url            string
+	UserID         uuid.NullUUID
+	LastFetchedAt  sql.NullTime
+	ParseRecovered bool
+	NextFetchAt    sql.NullTime
+	RetiredAt      sql.NullTime
+	CanonicalUrl   string
+	OrphanedAt     sql.NullTime
 }
 
-type FeedFollow struct {
+type FeedAlias struct {
+	Url       string
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+}
+
+type FeedCredential struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	Kind      string
+	Name      string
+	Secret    []byte
 }
 
-type Post struct {
+type FeedFetch struct {
+	ID         uuid.UUID
+	FeedID     uuid.UUID
+	FetchedAt  time.Time
+	DurationMs int32
+	StatusCode sql.NullInt32
+	Bytes      int64
+	Error      sql.NullString
+}
+
+type FeedFilter struct {
 	ID          uuid.UUID
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
 	FeedID      uuid.UUID
+	Kind        string
+	Pattern     string
+	Replacement string
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+type FeedRetention struct {
+	FeedID        uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	MaxPosts      sql.NullInt32
+	MaxAgeSeconds sql.NullInt64
+}
+
+type Post struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          sql.NullString
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	PublishedAtEstimated bool
+	ImageUrl             sql.NullString
+	ImagePath            sql.NullString
+}
+
+type PostAuthor struct {
+	PostID uuid.UUID
+	Name   string
+}
+
+type PostEnclosure struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        string
+	LengthBytes     sql.NullInt64
+	DurationSeconds sql.NullInt32
+}
+
+type PostEpisode struct {
+	PostID          uuid.UUID
+	DurationSeconds sql.NullInt32
+	Episode         sql.NullInt32
+	Season          sql.NullInt32
+	ImageUrl        sql.NullString
+	TranscriptUrl   sql.NullString
+	TranscriptType  sql.NullString
+	ChaptersUrl     sql.NullString
+}
+
+type PostKeep struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type PostTag struct {
+	PostID uuid.UUID
+	Tag    string
+}
+
+type PrunedPost struct {
+	Url      string
+	FeedID   uuid.UUID
+	PrunedAt time.Time
+}
+
+type Rule struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Action    string
+	Field     string
+	MatchType string
+	Pattern   string
 }
 
 type User struct {
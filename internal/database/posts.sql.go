@@ -13,8 +13,77 @@ import (
 	"github.com/google/uuid"
 )
 
+const addPostAuthor = `-- name: AddPostAuthor :exec
+INSERT INTO post_authors (post_id, name)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (post_id, name) DO NOTHING
+`
+
+type AddPostAuthorParams struct {
+	PostID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) AddPostAuthor(ctx context.Context, arg AddPostAuthorParams) error {
+	_, err := q.db.ExecContext(ctx, addPostAuthor, arg.PostID, arg.Name)
+	return err
+}
+
+const addPostTag = `-- name: AddPostTag :exec
+INSERT INTO post_tags (post_id, tag)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (post_id, tag) DO NOTHING
+`
+
+type AddPostTagParams struct {
+	PostID uuid.UUID
+	Tag    string
+}
+
+func (q *Queries) AddPostTag(ctx context.Context, arg AddPostTagParams) error {
+	_, err := q.db.ExecContext(ctx, addPostTag, arg.PostID, arg.Tag)
+	return err
+}
+
+const countFeedPosts = `-- name: CountFeedPosts :one
+SELECT COUNT(*)
+FROM posts
+WHERE feed_id = $1
+`
+
+func (q *Queries) CountFeedPosts(ctx context.Context, feedID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedPosts, feedID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeedPostsSince = `-- name: CountFeedPostsSince :one
+SELECT COUNT(*)
+FROM posts
+WHERE feed_id = $1 AND published_at >= $2
+`
+
+type CountFeedPostsSinceParams struct {
+	FeedID      uuid.UUID
+	PublishedAt sql.NullTime
+}
+
+func (q *Queries) CountFeedPostsSince(ctx context.Context, arg CountFeedPostsSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedPostsSince, arg.FeedID, arg.PublishedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url)
 VALUES (
     $1,
     $2,
@@ -23,20 +92,26 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url, image_path
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          sql.NullString
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	PublishedAtEstimated bool
+	ImageUrl             sql.NullString
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -49,7 +124,64 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Content,
+		arg.PublishedAtEstimated,
+		arg.ImageUrl,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.PublishedAtEstimated,
+		&i.ImageUrl,
+		&i.ImagePath,
+	)
+	return i, err
+}
+
+const getNewestPostForFeed = `-- name: GetNewestPostForFeed :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url, image_path
+FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC NULLS LAST
+LIMIT 1
+`
+
+func (q *Queries) GetNewestPostForFeed(ctx context.Context, feedID uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getNewestPostForFeed, feedID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.PublishedAtEstimated,
+		&i.ImageUrl,
+		&i.ImagePath,
 	)
+	return i, err
+}
+
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url, image_path
+FROM posts
+WHERE id = $1
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -60,39 +192,90 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Content,
+		&i.PublishedAtEstimated,
+		&i.ImageUrl,
+		&i.ImagePath,
+	)
+	return i, err
+}
+
+const getPostByUrl = `-- name: GetPostByUrl :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url, image_path
+FROM posts
+WHERE url = $1
+`
+
+func (q *Queries) GetPostByUrl(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByUrl, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.PublishedAtEstimated,
+		&i.ImageUrl,
+		&i.ImagePath,
 	)
 	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, f.name as "feed_name"
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.published_at_estimated, p.image_url, p.image_path, f.name as "feed_name"
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
 JOIN feed_follows ff ON f.id = ff.feed_id
 WHERE ff.user_id = $1
-ORDER BY p.published_at DESC
-LIMIT $2
+AND ($2::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors pa
+    WHERE pa.post_id = p.id AND lower(pa.name) = lower($2::text)
+))
+AND ($3::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt
+    WHERE pt.post_id = p.id AND lower(pt.tag) = lower($3::text)
+))
+ORDER BY p.published_at DESC, p.id
+LIMIT $4 OFFSET $5
 `
 
 type GetPostsForUserParams struct {
 	UserID uuid.UUID
+	Author sql.NullString
+	Tag    sql.NullString
 	Limit  int32
+	Offset int32
 }
 
 type GetPostsForUserRow struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
-	FeedName    string
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          sql.NullString
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	PublishedAtEstimated bool
+	ImageUrl             sql.NullString
+	ImagePath            sql.NullString
+	FeedName             string
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.Author,
+		arg.Tag,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +292,206 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.PublishedAtEstimated,
+			&i.ImageUrl,
+			&i.ImagePath,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsForPost = `-- name: ListAuthorsForPost :many
+SELECT name
+FROM post_authors
+WHERE post_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListAuthorsForPost(ctx context.Context, postID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsForPost, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostImagePaths = `-- name: ListPostImagePaths :many
+SELECT image_path
+FROM posts
+WHERE image_path IS NOT NULL
+`
+
+func (q *Queries) ListPostImagePaths(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listPostImagePaths)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var image_path sql.NullString
+		if err := rows.Scan(&image_path); err != nil {
+			return nil, err
+		}
+		items = append(items, image_path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsForPost = `-- name: ListTagsForPost :many
+SELECT tag
+FROM post_tags
+WHERE post_id = $1
+ORDER BY tag
+`
+
+func (q *Queries) ListTagsForPost(ctx context.Context, postID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsForPost, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveFeedPosts = `-- name: MoveFeedPosts :execrows
+UPDATE posts
+SET feed_id = $1
+WHERE feed_id = $2
+`
+
+type MoveFeedPostsParams struct {
+	KeeperID    uuid.UUID
+	DuplicateID uuid.UUID
+}
+
+func (q *Queries) MoveFeedPosts(ctx context.Context, arg MoveFeedPostsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveFeedPosts, arg.KeeperID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchPostsForUser = `-- name: SearchPostsForUser :many
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.published_at_estimated, p.image_url, p.image_path, f.name as "feed_name"
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+AND (p.title ILIKE '%' || $2::text || '%' ESCAPE '\'
+    OR p.description ILIKE '%' || $2::text || '%' ESCAPE '\'
+    OR p.content ILIKE '%' || $2::text || '%' ESCAPE '\')
+AND ($3::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors pa
+    WHERE pa.post_id = p.id AND lower(pa.name) = lower($3::text)
+))
+AND ($4::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt
+    WHERE pt.post_id = p.id AND lower(pt.tag) = lower($4::text)
+))
+ORDER BY p.published_at DESC, p.id
+LIMIT $5
+`
+
+type SearchPostsForUserParams struct {
+	UserID uuid.UUID
+	Query  string
+	Author sql.NullString
+	Tag    sql.NullString
+	Limit  int32
+}
+
+type SearchPostsForUserRow struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          sql.NullString
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	PublishedAtEstimated bool
+	ImageUrl             sql.NullString
+	ImagePath            sql.NullString
+	FeedName             string
+}
+
+func (q *Queries) SearchPostsForUser(ctx context.Context, arg SearchPostsForUserParams) ([]SearchPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPostsForUser,
+		arg.UserID,
+		arg.Query,
+		arg.Author,
+		arg.Tag,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsForUserRow
+	for rows.Next() {
+		var i SearchPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.PublishedAtEstimated,
+			&i.ImageUrl,
+			&i.ImagePath,
 			&i.FeedName,
 		); err != nil {
 			return nil, err
@@ -123,3 +506,19 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const setPostImagePath = `-- name: SetPostImagePath :exec
+UPDATE posts
+SET image_path = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetPostImagePathParams struct {
+	ID        uuid.UUID
+	ImagePath sql.NullString
+}
+
+func (q *Queries) SetPostImagePath(ctx context.Context, arg SetPostImagePathParams) error {
+	_, err := q.db.ExecContext(ctx, setPostImagePath, arg.ID, arg.ImagePath)
+	return err
+}
@@ -0,0 +1,313 @@
+// Package render turns the HTML found in feed items into plain text suitable
+// for a terminal.
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTML renders an HTML fragment as wrapped plain text no wider than width
+// columns. Paragraphs are separated by blank lines, lists are bulleted or
+// numbered, preformatted blocks are indented without wrapping and links are
+// collected as numbered footnotes at the end.
+func HTML(src string, width int) string {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return src
+	}
+	if width < 20 {
+		width = 20
+	}
+	r := &renderer{width: width}
+	for _, n := range nodes {
+		r.walk(n)
+	}
+	r.flush()
+	if len(r.links) > 0 {
+		r.out.WriteString("\n\n")
+		for i, link := range r.links {
+			if i > 0 {
+				r.out.WriteString("\n")
+			}
+			fmt.Fprintf(&r.out, "[%d] %s", i+1, link)
+		}
+	}
+	return r.out.String()
+}
+
+type renderer struct {
+	width  int
+	out    strings.Builder
+	inline strings.Builder
+	links  []string
+
+	// indent prefixes every line of the current block; marker, when set,
+	// replaces the end of indent on the block's first line (list bullets).
+	indent string
+	marker string
+	tight  bool
+	depth  int
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.walk(c)
+		}
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Iframe, atom.Object, atom.Noscript:
+		return
+	case atom.Br:
+		r.inline.WriteString("\n")
+		return
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			r.text(" [image: " + alt + "] ")
+		}
+		return
+	case atom.A:
+		r.children(n)
+		href := strings.TrimSpace(attr(n, "href"))
+		if href != "" && !strings.HasPrefix(href, "#") && !strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			fmt.Fprintf(&r.inline, "[%d]", r.link(href))
+		}
+		return
+	case atom.Pre:
+		r.flush()
+		r.pre(n)
+		return
+	case atom.Ul, atom.Ol:
+		r.flush()
+		r.list(n)
+		return
+	case atom.Blockquote:
+		r.flush()
+		saved := r.indent
+		r.indent += "> "
+		r.children(n)
+		r.flush()
+		r.indent = saved
+		return
+	case atom.Hr:
+		r.flush()
+		r.block(strings.Repeat("-", max(1, min(r.width-len(r.indent), 40))))
+		return
+	}
+
+	if isBlock(n.DataAtom) {
+		r.flush()
+		r.children(n)
+		r.flush()
+		return
+	}
+	r.children(n)
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+}
+
+// text appends character data to the current paragraph, collapsing runs of
+// whitespace the way a browser would.
+func (r *renderer) text(s string) {
+	if s == "" {
+		return
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		r.inline.WriteString(" ")
+		return
+	}
+	if isSpace(s[0]) {
+		r.inline.WriteString(" ")
+	}
+	r.inline.WriteString(strings.Join(fields, " "))
+	if isSpace(s[len(s)-1]) {
+		r.inline.WriteString(" ")
+	}
+}
+
+func (r *renderer) link(href string) int {
+	for i, existing := range r.links {
+		if existing == href {
+			return i + 1
+		}
+	}
+	r.links = append(r.links, href)
+	return len(r.links)
+}
+
+func (r *renderer) list(n *html.Node) {
+	savedIndent, savedTight := r.indent, r.tight
+	if r.depth > 0 {
+		// Nested lists hug their parent item.
+		r.tight = true
+	}
+	r.depth++
+	defer func() { r.depth-- }()
+	ordered := n.DataAtom == atom.Ol
+	index := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			r.walk(c)
+			continue
+		}
+		index++
+		marker := "* "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", index)
+		}
+		r.indent = savedIndent + strings.Repeat(" ", len(marker))
+		r.marker = marker
+		r.children(c)
+		r.flush()
+		r.marker = ""
+		r.tight = true
+	}
+	r.indent, r.tight = savedIndent, savedTight
+}
+
+func (r *renderer) pre(n *html.Node) {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Br {
+			sb.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	code := strings.Trim(sb.String(), "\n")
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+	r.separate()
+	for i, line := range strings.Split(code, "\n") {
+		if i > 0 {
+			r.out.WriteString("\n")
+		}
+		r.out.WriteString(strings.TrimRight(r.indent+"    "+line, " "))
+	}
+}
+
+// flush wraps and writes the pending paragraph.
+func (r *renderer) flush() {
+	text := r.inline.String()
+	r.inline.Reset()
+	var lines []string
+	for _, hard := range strings.Split(text, "\n") {
+		hard = strings.TrimSpace(hard)
+		if hard == "" {
+			continue
+		}
+		lines = append(lines, wrap(hard, r.width-utf8.RuneCountInString(r.indent))...)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	r.block(lines...)
+}
+
+func (r *renderer) block(lines ...string) {
+	r.separate()
+	for i, line := range lines {
+		prefix := r.indent
+		if i == 0 && r.marker != "" {
+			prefix = r.indent[:len(r.indent)-len(r.marker)] + r.marker
+			r.marker = ""
+		}
+		if i > 0 {
+			r.out.WriteString("\n")
+		}
+		r.out.WriteString(prefix + line)
+	}
+}
+
+func (r *renderer) separate() {
+	if r.out.Len() == 0 {
+		return
+	}
+	if r.tight {
+		r.out.WriteString("\n")
+		r.tight = false
+		return
+	}
+	r.out.WriteString("\n\n")
+}
+
+// wrap splits text into lines of at most width runes, breaking on spaces.
+// Words longer than width are placed on a line of their own.
+func wrap(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var lines []string
+	var line strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(text) {
+		wordLen := utf8.RuneCountInString(word)
+		if lineLen > 0 && lineLen+1+wordLen > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineLen = 0
+		}
+		if lineLen > 0 {
+			line.WriteString(" ")
+			lineLen++
+		}
+		line.WriteString(word)
+		lineLen += wordLen
+	}
+	if lineLen > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Table, atom.Tr, atom.Figure, atom.Figcaption, atom.Dl, atom.Dt, atom.Dd,
+		atom.Li, atom.Aside, atom.Nav, atom.Main:
+		return true
+	}
+	return false
+}
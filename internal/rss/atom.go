@@ -0,0 +1,104 @@
+package rss
+
+// atomFeed is the subset of an Atom 1.0 document that gator understands. It is
+// converted into an RSSFeed so the rest of the program only deals with one shape.
+type atomFeed struct {
+	Base     string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle"`
+	Authors  []atomPerson `xml:"author"`
+	Links    []atomLink   `xml:"link"`
+	Entries  []atomEntry  `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomEntry struct {
+	Base       string           `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title      string           `xml:"title"`
+	Links      []atomLink       `xml:"link"`
+	Summary    atomText         `xml:"summary"`
+	Content    atomText         `xml:"content"`
+	Authors    []atomPerson     `xml:"author"`
+	Categories []atomCategory   `xml:"category"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Published  string           `xml:"published"`
+	Updated    string           `xml:"updated"`
+}
+
+// atomText holds an Atom text construct. XHTML content is kept as markup,
+// text and html content as decoded character data.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return t.Inner
+	}
+	return t.Text
+}
+
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func (a *atomFeed) toRSS() *RSSFeed {
+	feed := &RSSFeed{Base: a.Base}
+	feed.Channel.Title = a.Title
+	feed.Channel.Link = alternateLink(a.Links)
+	feed.Channel.Description = a.Subtitle
+	for _, entry := range a.Entries {
+		item := RSSItem{
+			Base:            entry.Base,
+			Title:           entry.Title,
+			Link:            alternateLink(entry.Links),
+			Description:     entry.Summary.String(),
+			Content:         entry.Content.String(),
+			PubDate:         entry.Published,
+			Updated:         entry.Updated,
+			MediaThumbnails: entry.Thumbnails,
+		}
+		authors := entry.Authors
+		if len(authors) == 0 {
+			// Entries inherit the feed's authors when they have none.
+			authors = a.Authors
+		}
+		for _, author := range authors {
+			item.AuthorList = append(item.AuthorList, author.Name)
+		}
+		for _, category := range entry.Categories {
+			if category.Term != "" {
+				item.CategoryList = append(item.CategoryList, category.Term)
+			} else {
+				item.CategoryList = append(item.CategoryList, category.Label)
+			}
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				item.RSSEnclosures = append(item.RSSEnclosures, rssEnclosure{
+					URL:    link.Href,
+					Type:   link.Type,
+					Length: link.Length,
+				})
+			}
+		}
+		feed.Channel.Item = append(feed.Channel.Item, item)
+	}
+	return feed
+}
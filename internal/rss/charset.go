@@ -0,0 +1,53 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// newFeedDecoder returns an XML decoder that transcodes body to UTF-8. A
+// charset in the HTTP Content-Type header takes precedence over the encoding
+// declared in the XML prolog, as RFC 7303 requires; otherwise the prolog is
+// honored.
+func newFeedDecoder(body io.Reader, contentType string) (*xml.Decoder, error) {
+	label := contentTypeCharset(contentType)
+	if label == "" {
+		decoder := xml.NewDecoder(body)
+		decoder.CharsetReader = charset.NewReaderLabel
+		return decoder, nil
+	}
+	if !isUTF8(label) {
+		r, err := charset.NewReaderLabel(label, body)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported charset %q: %w", label, err)
+		}
+		body = r
+	}
+	decoder := xml.NewDecoder(body)
+	// The body is already UTF-8, so ignore whatever the prolog claims.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder, nil
+}
+
+func contentTypeCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(params["charset"])
+}
+
+func isUTF8(label string) bool {
+	label = strings.ToLower(label)
+	return label == "utf-8" || label == "utf8"
+}
@@ -0,0 +1,105 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Credentials authenticate requests for a private feed. Any combination may
+// be set; the zero value sends no credentials.
+type Credentials struct {
+	Username string
+	Password string
+	// BearerToken is sent as "Authorization: Bearer <token>" and takes
+	// precedence over basic auth.
+	BearerToken string
+	// Query parameters are added to the feed URL, e.g. a secret API key.
+	Query  url.Values
+	Header http.Header
+}
+
+// apply adds the credentials to req.
+func (c Credentials) apply(req *http.Request) {
+	for name, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+	if len(c.Query) > 0 {
+		query := req.URL.Query()
+		for name, values := range c.Query {
+			query[name] = values
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
+// credentialHeadersKey is the request context key holding the names of the
+// headers set from Credentials.Header.
+type credentialHeadersKey struct{}
+
+// withHeaderNames records the names of the credential headers in ctx so a
+// redirect to another host can strip them.
+func (c Credentials) withHeaderNames(ctx context.Context) context.Context {
+	if len(c.Header) == 0 {
+		return ctx
+	}
+	names := make([]string, 0, len(c.Header))
+	for name := range c.Header {
+		names = append(names, name)
+	}
+	return context.WithValue(ctx, credentialHeadersKey{}, names)
+}
+
+// stripCredentials removes credential headers from a redirected request
+// when it leaves the original host. net/http only drops Authorization and
+// cookies, and not for subdomains.
+func stripCredentials(req *http.Request, via []*http.Request) {
+	if len(via) == 0 || req.URL.Hostname() == via[0].URL.Hostname() {
+		return
+	}
+	names, _ := req.Context().Value(credentialHeadersKey{}).([]string)
+	for _, name := range names {
+		req.Header.Del(name)
+	}
+	req.Header.Del("Authorization")
+}
+
+// redactURL returns u with the secret query parameters removed, so it
+// can be shown, stored or used to resolve links.
+func (c Credentials) redactURL(u *url.URL) *url.URL {
+	if len(c.Query) == 0 || u.RawQuery == "" {
+		return u
+	}
+	redacted := *u
+	query := redacted.Query()
+	for name := range c.Query {
+		query.Del(name)
+	}
+	redacted.RawQuery = query.Encode()
+	return &redacted
+}
+
+// redactError removes secret query parameters from the URL in a transport
+// error.
+func (c Credentials) redactError(err error) error {
+	var urlErr *url.Error
+	if len(c.Query) == 0 || !errors.As(err, &urlErr) {
+		return err
+	}
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		urlErr.URL = c.redactURL(u).String()
+	} else {
+		urlErr.URL = strings.SplitN(urlErr.URL, "?", 2)[0]
+	}
+	return err
+}
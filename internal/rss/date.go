@@ -0,0 +1,158 @@
+package rss
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// zoneOffsets maps the named time zones seen in feeds to their UTC offsets.
+// time.Parse only knows the offset of the local zone's abbreviations, so
+// names are rewritten to numeric offsets before parsing.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"WET":  "+0000",
+	"WEST": "+0100",
+	"BST":  "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"MET":  "+0100",
+	"MEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"IST":  "+0530",
+	"SGT":  "+0800",
+	"HKT":  "+0800",
+	"AWST": "+0800",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"ACST": "+0930",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+	"HST":  "-1000",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"AST":  "-0400",
+	"ADT":  "-0300",
+	"NST":  "-0330",
+	"NDT":  "-0230",
+}
+
+var (
+	trailingZone = regexp.MustCompile(`\s*\(?([A-Za-z]{1,5})\)?$`)
+	leadingDay   = regexp.MustCompile(`^[A-Za-z]+,?\s+`)
+)
+
+// dateLayouts are tried in order after the weekday has been stripped and any
+// named zone replaced with a numeric offset. Layouts without a zone are
+// interpreted as UTC.
+var dateLayouts = []string{
+	// RFC 822 / 1123 and friends. "2" accepts one or two digit days and "06"
+	// two digit years.
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 2006 15:04:05 -07:00",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 06 15:04:05",
+	"2 January 2006 15:04:05",
+	"2 Jan 2006",
+	"2 January 2006",
+	// RFC 850.
+	"2-Jan-06 15:04:05 -0700",
+	"2-Jan-2006 15:04:05 -0700",
+	// ANSI C asctime.
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"January 2, 2006 15:04:05 -0700",
+	"January 2, 2006",
+	// ISO 8601 / RFC 3339.
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02T15:04:05 -0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// ItemDate returns the first parseable date of an item, trying pubDate, then
+// dc:date, then Atom updated.
+func ItemDate(item RSSItem) (time.Time, error) {
+	var firstErr error
+	for _, value := range []string{item.PubDate, item.DCDate, item.Updated} {
+		if value == "" {
+			continue
+		}
+		t, err := ParseDate(value)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = fmt.Errorf("item has no date")
+	}
+	return time.Time{}, firstErr
+}
+
+// ParseDate parses the many date formats found in RSS pubDate, Dublin Core
+// dc:date and Atom published/updated elements.
+func ParseDate(value string) (time.Time, error) {
+	normalized := strings.Join(strings.Fields(value), " ")
+	if normalized == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+	normalized = leadingDay.ReplaceAllStringFunc(normalized, func(day string) string {
+		// Only strip real weekday names, not month names like "June 3".
+		name := strings.ToLower(strings.TrimRight(day, ", "))
+		for _, weekday := range []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"} {
+			if strings.HasPrefix(name, weekday) {
+				return ""
+			}
+		}
+		return day
+	})
+	if m := trailingZone.FindStringSubmatchIndex(normalized); m != nil {
+		name := strings.ToUpper(normalized[m[2]:m[3]])
+		if offset, ok := zoneOffsets[name]; ok {
+			normalized = normalized[:m[0]] + " " + offset
+		}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date format %q", value)
+}
@@ -0,0 +1,101 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rssEnclosure is an RSS 2.0 <enclosure> element.
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// mediaContent is a Media RSS <media:content> element.
+type mediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+// Enclosure is a media file attached to an item, such as a podcast episode.
+// Length and Duration are zero when the feed does not provide them.
+type Enclosure struct {
+	URL      string
+	Type     string
+	Length   int64
+	Duration time.Duration
+}
+
+// Enclosures returns the item's media files from <enclosure>, <media:content>
+// and Atom enclosure links, without duplicates. The iTunes duration is
+// applied to enclosures that do not state their own.
+func (item RSSItem) Enclosures() []Enclosure {
+	var enclosures []Enclosure
+	seen := map[string]bool{}
+	add := func(e Enclosure) {
+		e.URL = strings.TrimSpace(e.URL)
+		if e.URL == "" || seen[e.URL] {
+			return
+		}
+		seen[e.URL] = true
+		enclosures = append(enclosures, e)
+	}
+	itunesDuration, _ := ParseDuration(item.ITunesDuration)
+	for _, e := range item.RSSEnclosures {
+		add(Enclosure{
+			URL:      e.URL,
+			Type:     e.Type,
+			Length:   parseLength(e.Length),
+			Duration: itunesDuration,
+		})
+	}
+	media := append(append([]mediaContent{}, item.MediaContent...), item.MediaGroup.Content...)
+	for _, m := range media {
+		duration, _ := ParseDuration(m.Duration)
+		if duration == 0 {
+			duration = itunesDuration
+		}
+		add(Enclosure{
+			URL:      m.URL,
+			Type:     m.Type,
+			Length:   parseLength(m.FileSize),
+			Duration: duration,
+		})
+	}
+	return enclosures
+}
+
+// ParseDuration parses podcast durations written as seconds ("3600"),
+// "MM:SS" or "HH:MM:SS", with optional fractional seconds.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, strconv.ErrSyntax
+	}
+	var total float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0, strconv.ErrSyntax
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total * float64(time.Second)), nil
+}
+
+func parseLength(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
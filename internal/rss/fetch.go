@@ -3,35 +3,252 @@ package rss
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"html"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
 )
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-	if err != nil {
+// DefaultUserAgent is sent when FetcherConfig does not set one.
+const DefaultUserAgent = "Gator/1.0"
+
+// FetcherConfig configures a Fetcher. Zero values use sensible defaults.
+type FetcherConfig struct {
+	UserAgent string
+	// Proxy is the URL of an HTTP proxy. When empty the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables are honored.
+	Proxy  string
+	Limits Limits
+	// HostRate is the sustained number of requests per second allowed to
+	// a single host, and HostBurst how many may be made at once. A zero
+	// HostRate disables rate limiting.
+	HostRate  float64
+	HostBurst int
+	// MaxConnsPerHost caps simultaneous connections to a single host. Zero
+	// means no limit.
+	MaxConnsPerHost int
+	// AllowedNetworks are exempt from the guard that blocks connections to
+	// loopback, private, link-local and other internal addresses.
+	AllowedNetworks []netip.Prefix
+}
+
+// Fetcher downloads feeds and other resources over a single pooled HTTP
+// client so connections are reused across requests. It is safe for
+// concurrent use.
+type Fetcher struct {
+	client    *http.Client
+	userAgent string
+	limits    Limits
+	hosts     *hostLimiter
+	guard     *addressGuard
+	proxy     func(*http.Request) (*url.URL, error)
+}
+
+// NewFetcher builds a Fetcher from cfg.
+func NewFetcher(cfg FetcherConfig) (*Fetcher, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %s: %w", cfg.Proxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	guard := &addressGuard{allowed: cfg.AllowedNetworks}
+	dialer := &proxyDialer{
+		guarded: &net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   guard.control,
+		},
+		direct: &net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+	proxy = dialer.proxy(proxy)
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   4,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		// Feed responses are decoded by FetchFeed itself so that brotli and
+		// deflate are supported alongside gzip.
+		DisableCompression: true,
+	}
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	f := &Fetcher{
+		userAgent: userAgent,
+		limits:    cfg.Limits.withDefaults(),
+		guard:     guard,
+		proxy:     proxy,
+	}
+	f.client = &http.Client{
+		Transport:     transport,
+		CheckRedirect: f.checkRedirect,
+	}
+	if cfg.HostRate > 0 {
+		f.hosts = newHostLimiter(cfg.HostRate, cfg.HostBurst)
+	}
+	return f, nil
+}
+
+// Do sends req with the fetcher's User-Agent unless the request sets its own,
+// first checking its scheme and waiting for the per-host rate limit. No timeout or size limit is
+// applied, so it is suitable for large downloads.
+func (f *Fetcher) Do(req *http.Request) (*http.Response, error) {
+	if err := f.checkTarget(req); err != nil {
 		return nil, err
 	}
-	client := &http.Client{}
-	req.Header.Set("User-Agent", "Gator/1.0")
-	resp, err := client.Do(req)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	if f.hosts != nil {
+		if err := f.hosts.wait(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return f.client.Do(req)
+}
+
+// FetchFeed downloads and parses the feed at feedURL within the fetcher's
+// limits, authenticating with creds. Secret query parameters never appear in
+// errors, MovedTo or resolved links.
+func (f *Fetcher) FetchFeed(ctx context.Context, feedURL string, creds Credentials) (*RSSFeed, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.limits.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(creds.withHeaderNames(ctx), "GET", feedURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	creds.apply(req)
+	resp, err := f.Do(req)
+	if err != nil {
+		return nil, creds.redactError(err)
+	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch RSS feed: %s", resp.Status)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
-	feed := &RSSFeed{}
-	if err := xml.NewDecoder(resp.Body).Decode(feed); err != nil {
+	if resp.ContentLength > f.limits.MaxBytes {
+		return nil, &TooLargeError{Limit: f.limits.MaxBytes}
+	}
+	counted := &countingReader{r: resp.Body}
+	body, err := decodeBody(counted, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode RSS feed body: %w", err)
+	}
+	defer body.Close()
+	data, err := readLimited(body, f.limits.MaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RSS feed: %w", creds.redactError(err))
+	}
+	feed, err := parseFeed(data, resp.Header.Get("Content-Type"))
+	if err != nil {
 		return nil, fmt.Errorf("failed to decode RSS feed: %w", err)
 	}
+	feed.Bytes = counted.n
+	if len(feed.Channel.Item) > f.limits.MaxItems {
+		feed.Truncated = len(feed.Channel.Item) - f.limits.MaxItems
+		feed.Channel.Item = feed.Channel.Item[:f.limits.MaxItems]
+	}
+	if moved := permanentRedirect(resp); moved != "" {
+		if u, err := url.Parse(moved); err == nil {
+			feed.MovedTo = creds.redactURL(u).String()
+		}
+	}
+	if err := feed.ResolveLinks(creds.redactURL(resp.Request.URL).String()); err != nil {
+		return nil, err
+	}
+	// Titles are plain text that often still carries entities. Item bodies
+	// are HTML the XML decoder has already unescaped once; unescaping them
+	// again would turn escaped markup, such as code samples, into real tags.
 	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
 	feed.Channel.Description = html.UnescapeString(feed.Channel.Description)
 	for i := range feed.Channel.Item {
 		feed.Channel.Item[i].Title = html.UnescapeString(feed.Channel.Item[i].Title)
-		feed.Channel.Item[i].Description = html.UnescapeString(feed.Channel.Item[i].Description)
 	}
 	return feed, nil
 }
+
+// checkRedirect vets every redirect like the original request and keeps
+// credentials from following it to another host.
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	stripCredentials(req, via)
+	return f.checkTarget(req)
+}
+
+// checkTarget rejects URLs that are not http or https. Direct connections are
+// vetted by the address guard in the dialer, but when a proxy is used the
+// dialer only sees the proxy, so the target host is resolved and checked here.
+func (f *Fetcher) checkTarget(req *http.Request) error {
+	if err := checkScheme(req.URL); err != nil {
+		return err
+	}
+	proxyURL, err := f.proxy(req)
+	if err != nil || proxyURL == nil {
+		return err
+	}
+	host := req.URL.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return f.guard.check(addr)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(req.Context(), "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := f.guard.check(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeFeed decodes an RSS 2.0 or Atom document, converting Atom into the
+// RSSFeed shape.
+func decodeFeed(decoder *xml.Decoder) (*RSSFeed, error) {
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "feed" {
+			atom := &atomFeed{}
+			if err := decoder.DecodeElement(atom, &start); err != nil {
+				return nil, err
+			}
+			return atom.toRSS(), nil
+		}
+		feed := &RSSFeed{}
+		if err := decoder.DecodeElement(feed, &start); err != nil {
+			return nil, err
+		}
+		feed.Channel.Link = plainLink(feed.Channel.Links)
+		return feed, nil
+	}
+}
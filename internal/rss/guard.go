@@ -0,0 +1,145 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+)
+
+// blockedPrefixes are ranges that are not covered by the netip predicates
+// used in addressGuard.blocked.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),          // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),      // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),       // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),      // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),        // reserved
+	netip.MustParsePrefix("255.255.255.255/32"), // limited broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),       // NAT64, which can reach internal IPv4 addresses
+}
+
+// BlockedAddressError is returned when a request would connect to a
+// loopback, private, link-local or otherwise internal address.
+type BlockedAddressError struct {
+	Addr netip.Addr
+}
+
+func (e *BlockedAddressError) Error() string {
+	return fmt.Sprintf("refusing to connect to internal address %s", e.Addr)
+}
+
+// addressGuard vets every address the fetcher dials. Because it runs after
+// DNS resolution and on every connection, it also covers redirects and DNS
+// names that resolve to internal addresses.
+type addressGuard struct {
+	allowed []netip.Prefix
+}
+
+func (g *addressGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	return g.check(addr)
+}
+
+// check returns a BlockedAddressError if addr is internal and not allowed.
+func (g *addressGuard) check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if blocked(addr) {
+		return &BlockedAddressError{Addr: addr}
+	}
+	return nil
+}
+
+func blocked(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL reports whether rawURL is an absolute http or https URL, the only
+// kind the fetcher will request.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url %s: %w", rawURL, err)
+	}
+	return checkScheme(u)
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q: only http and https are allowed", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url %s has no host", u.Redacted())
+	}
+	return nil
+}
+
+// proxyDialer dials through the address guard, except for connections to the
+// proxies the transport has been told to use. Proxies commonly run on a
+// private or loopback address, and the targets behind them are checked by
+// Fetcher.checkTarget instead.
+type proxyDialer struct {
+	guarded *net.Dialer
+	direct  *net.Dialer
+	proxies sync.Map // "host:port" of every proxy returned by proxy
+}
+
+// proxy wraps a transport's Proxy function to remember the proxies it picks.
+func (d *proxyDialer) proxy(next func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := next(req)
+		if err == nil && proxyURL != nil {
+			d.proxies.Store(proxyAddr(proxyURL), true)
+		}
+		return proxyURL, err
+	}
+}
+
+func (d *proxyDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if _, ok := d.proxies.Load(address); ok {
+		return d.direct.DialContext(ctx, network, address)
+	}
+	return d.guarded.DialContext(ctx, network, address)
+}
+
+// proxyAddr is the address the transport dials to reach a proxy.
+func proxyAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
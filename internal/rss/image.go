@@ -0,0 +1,100 @@
+package rss
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// mediaThumbnail is a Media RSS <media:thumbnail> element.
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// Image returns the URL of an image representing the item, or "" if it has
+// none. Sources are tried in order: media:thumbnail, image media:content,
+// image enclosures, then the first <img> in the content or description. Only
+// absolute http and https URLs are returned.
+func (item RSSItem) Image() string {
+	thumbnails := append(append([]mediaThumbnail{}, item.MediaThumbnails...), item.MediaGroup.Thumbnails...)
+	for _, t := range thumbnails {
+		if u := imageURL(t.URL); u != "" {
+			return u
+		}
+	}
+	media := append(append([]mediaContent{}, item.MediaContent...), item.MediaGroup.Content...)
+	for _, m := range media {
+		if m.Medium == "image" || isImageType(m.Type) {
+			if u := imageURL(m.URL); u != "" {
+				return u
+			}
+		}
+	}
+	for _, e := range item.RSSEnclosures {
+		if isImageType(e.Type) {
+			if u := imageURL(e.URL); u != "" {
+				return u
+			}
+		}
+	}
+	if src := firstImage(item.Content); src != "" {
+		return src
+	}
+	return firstImage(item.Description)
+}
+
+func imageURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+func isImageType(mimeType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(mimeType)), "image/")
+}
+
+// firstImage returns the src of the first <img> in an HTML fragment, skipping
+// images declared as 1x1 or smaller, which are usually tracking pixels.
+func firstImage(fragment string) string {
+	if !strings.Contains(fragment, "<img") && !strings.Contains(fragment, "<IMG") {
+		return ""
+	}
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if tok.DataAtom != atom.Img {
+				continue
+			}
+			var src, width, height string
+			for _, a := range tok.Attr {
+				switch a.Key {
+				case "src":
+					src = a.Val
+				case "width":
+					width = strings.TrimSpace(a.Val)
+				case "height":
+					height = strings.TrimSpace(a.Val)
+				}
+			}
+			if isTiny(width) && isTiny(height) {
+				continue
+			}
+			if u := imageURL(src); u != "" {
+				return u
+			}
+		}
+	}
+}
+
+func isTiny(dimension string) bool {
+	dimension = strings.TrimSuffix(dimension, "px")
+	return dimension == "0" || dimension == "1"
+}
@@ -0,0 +1,51 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// parseFeed decodes data as a feed, first strictly and then, if that fails,
+// in a tolerant mode that accepts HTML entities such as &nbsp;, stray
+// ampersands, control characters and byte order marks. The returned feed's
+// Recovered field reports whether the tolerant mode was needed.
+func parseFeed(data []byte, contentType string) (*RSSFeed, error) {
+	decoder, err := newFeedDecoder(bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, err
+	}
+	feed, strictErr := decodeFeed(decoder)
+	if strictErr == nil {
+		return feed, nil
+	}
+	decoder, err = newFeedDecoder(bytes.NewReader(scrub(data)), contentType)
+	if err != nil {
+		return nil, err
+	}
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	feed, err = decodeFeed(decoder)
+	if err != nil {
+		// Report the strict error; it usually points at the real problem.
+		return nil, strictErr
+	}
+	feed.Recovered = true
+	return feed, nil
+}
+
+// scrub strips a leading byte order mark and the control characters that
+// XML 1.0 forbids. Only ASCII control bytes are removed, so multi-byte
+// encodings are left intact.
+func scrub(data []byte) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	cleaned := make([]byte, 0, len(data))
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			continue
+		}
+		cleaned = append(cleaned, b)
+	}
+	return cleaned
+}
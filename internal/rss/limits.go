@@ -0,0 +1,73 @@
+package rss
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Limits bounds the resources a single feed fetch may use. Zero fields fall
+// back to the matching DefaultLimits value.
+type Limits struct {
+	// Timeout covers the whole request, including reading the body.
+	Timeout time.Duration
+	// MaxBytes is the largest response body that will be read.
+	MaxBytes int64
+	// MaxItems is the most items kept from one fetch; later items are
+	// discarded.
+	MaxItems int
+}
+
+// DefaultLimits are applied to any limit left unset.
+var DefaultLimits = Limits{
+	Timeout:  30 * time.Second,
+	MaxBytes: 10 << 20,
+	MaxItems: 500,
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultLimits.Timeout
+	}
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = DefaultLimits.MaxBytes
+	}
+	if l.MaxItems <= 0 {
+		l.MaxItems = DefaultLimits.MaxItems
+	}
+	return l
+}
+
+// TooLargeError is returned when a feed's body exceeds Limits.MaxBytes.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("feed is larger than the %d byte limit", e.Limit)
+}
+
+// readLimited reads all of r, failing with a TooLargeError instead of
+// reading more than limit bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, &TooLargeError{Limit: limit}
+	}
+	return data, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
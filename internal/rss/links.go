@@ -0,0 +1,87 @@
+package rss
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResolveLinks makes the channel link and every item and enclosure link
+// absolute. Relative references are resolved against the xml:base in scope,
+// then the channel link, and finally feedURL. Each item's Base is replaced
+// with the absolute URL used, so relative links inside its description and
+// content can be resolved the same way.
+func (f *RSSFeed) ResolveLinks(feedURL string) error {
+	root, err := url.Parse(feedURL)
+	if err != nil {
+		return fmt.Errorf("invalid feed url %s: %w", feedURL, err)
+	}
+	if link := resolve(root, f.Channel.Link); link != nil {
+		f.Channel.Link = link.String()
+		if link.Scheme == "http" || link.Scheme == "https" {
+			root = link
+		}
+	}
+	channelBase := resolve(root, f.Base)
+	if channelBase == nil {
+		channelBase = root
+	}
+	if base := resolve(channelBase, f.Channel.Base); base != nil {
+		channelBase = base
+	}
+	for i := range f.Channel.Item {
+		item := &f.Channel.Item[i]
+		base := channelBase
+		if itemBase := resolve(channelBase, item.Base); itemBase != nil {
+			base = itemBase
+		}
+		item.Base = base.String()
+		if link := resolve(base, item.Link); link != nil {
+			item.Link = link.String()
+		}
+		for j := range item.RSSEnclosures {
+			if u := resolve(base, item.RSSEnclosures[j].URL); u != nil {
+				item.RSSEnclosures[j].URL = u.String()
+			}
+		}
+		for _, ref := range []*string{&item.ITunesImage.Href, &item.PodcastChapters.URL} {
+			if u := resolve(base, *ref); u != nil {
+				*ref = u.String()
+			}
+		}
+		for j := range item.PodcastTranscripts {
+			if u := resolve(base, item.PodcastTranscripts[j].URL); u != nil {
+				item.PodcastTranscripts[j].URL = u.String()
+			}
+		}
+		for _, media := range [][]mediaContent{item.MediaContent, item.MediaGroup.Content} {
+			for j := range media {
+				if u := resolve(base, media[j].URL); u != nil {
+					media[j].URL = u.String()
+				}
+			}
+		}
+		for _, thumbnails := range [][]mediaThumbnail{item.MediaThumbnails, item.MediaGroup.Thumbnails} {
+			for j := range thumbnails {
+				if u := resolve(base, thumbnails[j].URL); u != nil {
+					thumbnails[j].URL = u.String()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns ref resolved against base, or nil if ref is empty or not a
+// valid URL reference.
+func resolve(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil
+	}
+	return base.ResolveReference(u)
+}
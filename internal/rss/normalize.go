@@ -0,0 +1,73 @@
+package rss
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// NormalizeURL cleans up a feed URL without changing what it points to: the
+// scheme and host are lowercased, default ports and fragments are dropped
+// and an empty path becomes "/". The query is kept verbatim, since servers
+// may depend on its exact form.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := parseFeedURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	}
+	return u.String(), nil
+}
+
+// CanonicalURL returns the identity of a feed URL, used to detect duplicate
+// feeds. On top of NormalizeURL it ignores the scheme, a leading "www.",
+// credentials in the URL, trailing slashes and the order of query
+// parameters, so "http://www.example.com/feed/" and
+// "https://example.com/feed" are the same feed.
+func CanonicalURL(rawURL string) (string, error) {
+	u, err := parseFeedURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	canonical := host + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		canonical += "?" + u.Query().Encode()
+	}
+	return canonical, nil
+}
+
+// parseFeedURL parses and checks rawURL, applying the normalizations shared
+// by NormalizeURL and CanonicalURL.
+func parseFeedURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u, nil
+}
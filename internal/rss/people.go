@@ -0,0 +1,61 @@
+package rss
+
+import (
+	"regexp"
+	"strings"
+)
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// rssAuthor matches the RSS 2.0 convention "email (Full Name)".
+var rssAuthor = regexp.MustCompile(`^\S+@\S+\s*\((.+)\)$`)
+
+// Authors returns the item's author names from <author>, dc:creator and Atom
+// <author><name>, without duplicates. RSS author values of the form
+// "email (Name)" are reduced to the name.
+func (item RSSItem) Authors() []string {
+	var names []string
+	for _, author := range item.AuthorList {
+		author = strings.TrimSpace(author)
+		if m := rssAuthor.FindStringSubmatch(author); m != nil {
+			author = strings.TrimSpace(m[1])
+		}
+		names = appendUnique(names, author)
+	}
+	for _, creator := range item.DCCreators {
+		names = appendUnique(names, creator)
+	}
+	return names
+}
+
+// Categories returns the item's <category> values without duplicates.
+func (item RSSItem) Categories() []string {
+	var categories []string
+	for _, category := range item.CategoryList {
+		categories = appendUnique(categories, category)
+	}
+	return categories
+}
+
+// appendUnique appends value to list unless it is blank or already present,
+// ignoring case.
+func appendUnique(list []string, value string) []string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return list
+	}
+	for _, existing := range list {
+		if strings.EqualFold(existing, value) {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,61 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type podcastTranscript struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type podcastChapters struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// Episode is the podcast metadata of an item from the iTunes and Podcasting
+// 2.0 namespaces. Zero values mean the feed did not provide the field.
+type Episode struct {
+	Duration       time.Duration
+	Number         int
+	Season         int
+	ImageURL       string
+	TranscriptURL  string
+	TranscriptType string
+	ChaptersURL    string
+}
+
+// Episode returns the item's podcast metadata and whether it had any. When an
+// item lists several transcripts the first one is used.
+func (item RSSItem) Episode() (Episode, bool) {
+	episode := Episode{
+		Number:      parseCount(item.ITunesEpisode),
+		Season:      parseCount(item.ITunesSeason),
+		ImageURL:    strings.TrimSpace(item.ITunesImage.Href),
+		ChaptersURL: strings.TrimSpace(item.PodcastChapters.URL),
+	}
+	episode.Duration, _ = ParseDuration(item.ITunesDuration)
+	for _, transcript := range item.PodcastTranscripts {
+		if url := strings.TrimSpace(transcript.URL); url != "" {
+			episode.TranscriptURL = url
+			episode.TranscriptType = transcript.Type
+			break
+		}
+	}
+	return episode, episode != Episode{}
+}
+
+func parseCount(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,27 @@
+package rss
+
+import "net/http"
+
+// permanentRedirect returns the URL a feed has permanently moved to: the end
+// of the leading run of 301 and 308 redirects in resp's redirect chain. It
+// returns "" when the first redirect was temporary or there was none.
+func permanentRedirect(resp *http.Response) string {
+	// Walk back from the final request to the original one. Each request's
+	// Response is the redirect that caused it.
+	var chain []*http.Request
+	for req := resp.Request; req != nil; req = req.Response.Request {
+		chain = append(chain, req)
+		if req.Response == nil {
+			break
+		}
+	}
+	moved := ""
+	for i := len(chain) - 2; i >= 0; i-- {
+		code := chain[i].Response.StatusCode
+		if code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect {
+			break
+		}
+		moved = chain[i].URL.String()
+	}
+	return moved
+}
@@ -1,17 +1,80 @@
 package rss
 
+import (
+	"encoding/xml"
+	"strings"
+)
+
 type RSSFeed struct {
+	Base    string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []RSSItem `xml:"item"`
+		Base  string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+		Title string `xml:"title"`
+		// Link is the channel's own <link>, taken from Links once decoded.
+		Link string `xml:"-"`
+		// Links collects every element named link, which includes
+		// <atom:link rel="self"> since the tag cannot exclude namespaces.
+		Links       []channelLink `xml:"link"`
+		Description string        `xml:"description"`
+		Item        []RSSItem     `xml:"item"`
 	} `xml:"channel"`
+	// Recovered is set when the document was malformed and only parsed in
+	// tolerant mode.
+	Recovered bool `xml:"-"`
+	// Truncated is the number of items discarded because the feed had more
+	// than Limits.MaxItems.
+	Truncated int `xml:"-"`
+	// MovedTo is the URL the feed permanently redirected to, if any.
+	MovedTo string `xml:"-"`
+	// Bytes is the size of the response body as transferred, before it was
+	// decompressed.
+	Bytes int64 `xml:"-"`
+}
+
+type channelLink struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+}
+
+// plainLink returns the text of the first non-namespaced <link> element.
+func plainLink(links []channelLink) string {
+	for _, link := range links {
+		if link.XMLName.Space == "" && strings.TrimSpace(link.Text) != "" {
+			return strings.TrimSpace(link.Text)
+		}
+	}
+	return ""
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	// Base is the item's xml:base until ResolveLinks replaces it with the
+	// absolute URL that relative links in the item resolve against.
+	Base        string   `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string   `xml:"pubDate"`
+	DCDate      string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Updated     string   `xml:"http://www.w3.org/2005/Atom updated"`
+	Tags        []string `xml:"-"` // assigned by ingestion transforms
+
+	AuthorList   []string `xml:"author"`
+	DCCreators   []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	CategoryList []string `xml:"category"`
+
+	RSSEnclosures []rssEnclosure `xml:"enclosure"`
+	MediaContent  []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup    struct {
+		Content    []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+		Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	} `xml:"http://search.yahoo.com/mrss/ group"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	ITunesDuration  string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+
+	ITunesEpisode      string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesSeason       string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	ITunesImage        itunesImage         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	PodcastTranscripts []podcastTranscript `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+	PodcastChapters    podcastChapters     `xml:"https://podcastindex.org/namespace/1.0 chapters"`
 }
@@ -0,0 +1,107 @@
+package rss
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Transform rewrites or filters a feed item before it is stored. Apply returns
+// false when the item should be dropped.
+type Transform interface {
+	Apply(item *RSSItem) bool
+}
+
+// TransformFunc adapts an ordinary function to the Transform interface.
+type TransformFunc func(item *RSSItem) bool
+
+func (f TransformFunc) Apply(item *RSSItem) bool {
+	return f(item)
+}
+
+// ApplyTransforms runs transforms over item in order, stopping as soon as one
+// of them drops it.
+func ApplyTransforms(item *RSSItem, transforms []Transform) bool {
+	for _, t := range transforms {
+		if !t.Apply(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// DropMatching drops items whose title or description matches re.
+func DropMatching(re *regexp.Regexp) Transform {
+	return TransformFunc(func(item *RSSItem) bool {
+		return !re.MatchString(item.Title) && !re.MatchString(item.Description)
+	})
+}
+
+var trackingParams = []string{
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_cid",
+	"mc_eid",
+	"igshid",
+	"_hsenc",
+	"_hsmi",
+}
+
+// StripTrackingParams removes utm_* and other well-known tracking query
+// parameters, plus any extra names given, from the item link. The remaining
+// parameters are kept exactly as written, and a link without tracking
+// parameters is left untouched, so stored post URLs stay stable.
+func StripTrackingParams(extra ...string) Transform {
+	return TransformFunc(func(item *RSSItem) bool {
+		u, err := url.Parse(item.Link)
+		if err != nil || u.RawQuery == "" {
+			return true
+		}
+		pairs := strings.Split(u.RawQuery, "&")
+		kept := pairs[:0]
+		for _, pair := range pairs {
+			rawName, _, _ := strings.Cut(pair, "=")
+			name, err := url.QueryUnescape(rawName)
+			if err != nil {
+				name = rawName
+			}
+			lower := strings.ToLower(name)
+			if strings.HasPrefix(lower, "utm_") || slices.Contains(trackingParams, lower) || slices.Contains(extra, name) {
+				continue
+			}
+			kept = append(kept, pair)
+		}
+		if len(kept) == len(pairs) {
+			return true
+		}
+		u.RawQuery = strings.Join(kept, "&")
+		item.Link = u.String()
+		return true
+	})
+}
+
+// RewriteURL replaces matches of re in the item link with replacement, which
+// may reference capture groups as in regexp.ReplaceAllString.
+func RewriteURL(re *regexp.Regexp, replacement string) Transform {
+	return TransformFunc(func(item *RSSItem) bool {
+		item.Link = re.ReplaceAllString(item.Link, replacement)
+		return true
+	})
+}
+
+// Tag adds tag to every item whose title or description matches re, or to
+// every item when re is nil.
+func Tag(tag string, re *regexp.Regexp) Transform {
+	return TransformFunc(func(item *RSSItem) bool {
+		if re != nil && !re.MatchString(item.Title) && !re.MatchString(item.Description) {
+			return true
+		}
+		if !slices.Contains(item.Tags, tag) {
+			item.Tags = append(item.Tags, tag)
+		}
+		return true
+	})
+}
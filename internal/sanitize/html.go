@@ -0,0 +1,242 @@
+// Package sanitize cleans untrusted HTML from feeds before it is stored.
+package sanitize
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedElements maps each permitted element to the attributes it may keep.
+var allowedElements = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Abbr:       {"title"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+	atom.Ins:        nil,
+	atom.Kbd:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Samp:       nil,
+	atom.Small:      nil,
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Time:       {"datetime"},
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+}
+
+// droppedElements are removed together with everything inside them. Any other
+// element that is not allowed is unwrapped, keeping its children.
+var droppedElements = map[atom.Atom]bool{
+	atom.Applet:   true,
+	atom.Base:     true,
+	atom.Button:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Head:     true,
+	atom.Iframe:   true,
+	atom.Input:    true,
+	atom.Link:     true,
+	atom.Math:     true,
+	atom.Meta:     true,
+	atom.Noscript: true,
+	atom.Object:   true,
+	atom.Script:   true,
+	atom.Select:   true,
+	atom.Style:    true,
+	atom.Svg:      true,
+	atom.Template: true,
+	atom.Textarea: true,
+	atom.Title:    true,
+}
+
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+	"cite": true,
+}
+
+// HTML returns src with every element and attribute outside the allowlist
+// removed, tracking pixels dropped and links made absolute against base. A nil
+// base leaves relative links unresolved.
+func HTML(src string, base *url.URL) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return html.EscapeString(src)
+	}
+	out := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		clean(out, n, base)
+	}
+	var sb strings.Builder
+	for c := out.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&sb, c); err != nil {
+			return html.EscapeString(src)
+		}
+	}
+	return sb.String()
+}
+
+// clean appends a sanitized copy of n to parent.
+func clean(parent, n *html.Node, base *url.URL) {
+	switch n.Type {
+	case html.TextNode:
+		parent.AppendChild(&html.Node{Type: html.TextNode, Data: n.Data})
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		cleanChildren(parent, n, base)
+		return
+	default:
+		// Comments, doctypes and anything else are dropped.
+		return
+	}
+
+	if droppedElements[n.DataAtom] {
+		return
+	}
+	allowedAttrs, ok := allowedElements[n.DataAtom]
+	if !ok {
+		cleanChildren(parent, n, base)
+		return
+	}
+	el := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom}
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !slices.Contains(allowedAttrs, a.Key) {
+			continue
+		}
+		if urlAttributes[a.Key] {
+			resolved, ok := safeURL(a.Val, base, a.Key == "href")
+			if !ok {
+				continue
+			}
+			a.Val = resolved
+		}
+		el.Attr = append(el.Attr, html.Attribute{Key: a.Key, Val: a.Val})
+	}
+	switch n.DataAtom {
+	case atom.Img:
+		if attr(el, "src") == "" || isTrackingPixel(n) {
+			return
+		}
+	case atom.A:
+		if attr(el, "href") != "" {
+			el.Attr = append(el.Attr, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+		}
+	}
+	parent.AppendChild(el)
+	cleanChildren(el, n, base)
+}
+
+func cleanChildren(parent, n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		clean(parent, c, base)
+	}
+}
+
+// safeURL resolves raw against base and reports whether the result uses a
+// scheme that is safe to follow. mailto links are only allowed for href.
+func safeURL(raw string, base *url.URL, isHref bool) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String(), true
+	case "mailto":
+		return u.String(), isHref
+	case "":
+		// Still relative because no base was available.
+		return u.String(), true
+	default:
+		return "", false
+	}
+}
+
+// isTrackingPixel reports whether an image is declared as 1x1 or smaller, or
+// hidden with inline styles.
+func isTrackingPixel(n *html.Node) bool {
+	width, height := dimension(attr(n, "width")), dimension(attr(n, "height"))
+	if width >= 0 && width <= 1 && height >= 0 && height <= 1 {
+		return true
+	}
+	style := strings.ToLower(strings.ReplaceAll(attr(n, "style"), " ", ""))
+	return strings.Contains(style, "display:none") ||
+		strings.Contains(style, "visibility:hidden") ||
+		(strings.Contains(style, "width:1px") && strings.Contains(style, "height:1px")) ||
+		(strings.Contains(style, "width:0") && strings.Contains(style, "height:0"))
+}
+
+// dimension parses a width or height attribute, returning -1 if it is
+// missing or not a plain pixel count.
+func dimension(val string) int {
+	val = strings.TrimSuffix(strings.TrimSpace(val), "px")
+	if val == "" {
+		return -1
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
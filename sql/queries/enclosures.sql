@@ -0,0 +1,18 @@
+-- name: CreatePostEnclosure :exec
+INSERT INTO post_enclosures (id, created_at, post_id, url, mime_type, length_bytes, duration_seconds)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (post_id, url) DO NOTHING;
+
+-- name: ListEnclosuresForPost :many
+SELECT *
+FROM post_enclosures
+WHERE post_id = $1
+ORDER BY created_at, url;
\ No newline at end of file
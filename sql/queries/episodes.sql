@@ -0,0 +1,25 @@
+-- name: CreatePostEpisode :exec
+INSERT INTO post_episodes (post_id, duration_seconds, episode, season, image_url, transcript_url, transcript_type, chapters_url)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+ON CONFLICT (post_id) DO NOTHING;
+
+-- name: ListEpisodes :many
+SELECT p.id, p.title, p.url, p.published_at, f.name AS feed_name, e.duration_seconds, e.episode, e.season
+FROM post_episodes e
+JOIN posts p ON e.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+WHERE (sqlc.narg(feed_id)::uuid IS NULL AND f.id IN (
+    SELECT ff.feed_id FROM feed_follows ff WHERE ff.user_id = sqlc.arg(user_id)
+))
+OR f.id = sqlc.narg(feed_id)::uuid
+ORDER BY p.published_at DESC, p.id
+LIMIT sqlc.arg(max_results)::int;
\ No newline at end of file
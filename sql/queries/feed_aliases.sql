@@ -0,0 +1,29 @@
+-- name: CreateFeedAlias :exec
+INSERT INTO feed_aliases (url, created_at, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (url) DO UPDATE SET feed_id = EXCLUDED.feed_id, created_at = EXCLUDED.created_at;
+
+-- name: DeleteFeedAlias :exec
+DELETE FROM feed_aliases
+WHERE url = $1;
+
+-- name: GetFeedByAlias :one
+SELECT f.*
+FROM feeds f
+JOIN feed_aliases a ON a.feed_id = f.id
+WHERE a.url = $1;
+
+-- name: ListFeedAliases :many
+SELECT *
+FROM feed_aliases
+WHERE feed_id = $1
+ORDER BY created_at;
+
+-- name: MoveFeedAliases :exec
+UPDATE feed_aliases
+SET feed_id = sqlc.arg(keeper_id)
+WHERE feed_id = sqlc.arg(duplicate_id);
@@ -0,0 +1,32 @@
+-- name: UpsertFeedCredential :exec
+INSERT INTO feed_credentials (id, created_at, updated_at, feed_id, kind, name, secret)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (feed_id, kind, name) DO UPDATE
+SET secret = EXCLUDED.secret, updated_at = EXCLUDED.updated_at;
+
+-- name: ListFeedCredentials :many
+SELECT *
+FROM feed_credentials
+WHERE feed_id = $1
+ORDER BY kind, name;
+
+-- name: DeleteFeedCredentials :execrows
+DELETE FROM feed_credentials
+WHERE feed_id = $1;
+
+-- name: MoveFeedCredentials :execrows
+UPDATE feed_credentials c
+SET feed_id = sqlc.arg(keeper_id)
+WHERE c.feed_id = sqlc.arg(duplicate_id)
+AND NOT EXISTS (
+    SELECT 1 FROM feed_credentials k
+    WHERE k.feed_id = sqlc.arg(keeper_id) AND k.kind = c.kind AND k.name = c.name
+);
@@ -0,0 +1,44 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (id, feed_id, fetched_at, duration_ms, status_code, bytes, error)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+);
+
+-- name: PruneFeedFetches :exec
+DELETE FROM feed_fetches
+WHERE feed_id = $1 AND fetched_at < $2;
+
+-- name: ListFeedFetches :many
+SELECT *
+FROM feed_fetches
+WHERE feed_id = $1
+ORDER BY fetched_at DESC
+LIMIT $2;
+
+-- name: GetLastSuccessfulFeedFetch :one
+SELECT *
+FROM feed_fetches
+WHERE feed_id = $1 AND error IS NULL
+ORDER BY fetched_at DESC
+LIMIT 1;
+
+-- name: GetLastFailedFeedFetch :one
+SELECT *
+FROM feed_fetches
+WHERE feed_id = $1 AND error IS NOT NULL
+ORDER BY fetched_at DESC
+LIMIT 1;
+
+-- name: GetFeedFetchTotals :one
+SELECT
+    COUNT(*) AS fetches,
+    COALESCE(AVG(duration_ms), 0)::float8 AS avg_duration_ms,
+    COALESCE(SUM(bytes), 0)::bigint AS total_bytes
+FROM feed_fetches
+WHERE feed_id = $1;
@@ -0,0 +1,32 @@
+-- name: CreateFeedFilter :one
+INSERT INTO feed_filters (id, created_at, updated_at, feed_id, kind, pattern, replacement)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetFeedFilter :one
+SELECT *
+FROM feed_filters
+WHERE id = $1;
+
+-- name: ListFeedFilters :many
+SELECT *
+FROM feed_filters
+WHERE feed_id = $1
+ORDER BY created_at;
+
+-- name: DeleteFeedFilter :exec
+DELETE FROM feed_filters
+WHERE id = $1;
+
+-- name: MoveFeedFilters :execrows
+UPDATE feed_filters
+SET feed_id = sqlc.arg(keeper_id), updated_at = NOW()
+WHERE feed_id = sqlc.arg(duplicate_id);
@@ -24,7 +24,9 @@ INNER JOIN users ON inserted_feed_follow.user_id = users.id;
 
 -- name: GetFeedFollowsForUser :many
 SELECT
-    feeds.name AS feed_name
+    feeds.name AS feed_name,
+    feeds.url AS feed_url,
+    feeds.retired_at
 FROM feed_follows feed_follows
 JOIN feeds ON feed_follows.feed_id = feeds.id
 WHERE feed_follows.user_id = $1
@@ -33,4 +35,25 @@ ORDER BY feeds.name;
 -- name: DeleteFeedFollow :one
 DELETE FROM feed_follows
 WHERE feed_id = $1 AND user_id = $2
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: CountFeedFollows :one
+SELECT COUNT(*)
+FROM feed_follows
+WHERE feed_id = $1;
+
+-- name: MoveFeedFollows :execrows
+UPDATE feed_follows
+SET feed_id = sqlc.arg(keeper_id), updated_at = NOW()
+WHERE feed_id = sqlc.arg(duplicate_id)
+AND user_id NOT IN (
+    SELECT user_id FROM feed_follows WHERE feed_id = sqlc.arg(keeper_id)
+);
+
+-- name: GetEarliestOtherFollower :one
+SELECT u.*
+FROM feed_follows ff
+JOIN users u ON ff.user_id = u.id
+WHERE ff.feed_id = $1 AND ff.user_id <> $2
+ORDER BY ff.created_at
+LIMIT 1;
@@ -1,34 +1,116 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, canonical_url)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
 )
 RETURNING *;
 
 -- name: ListFeeds :many
 SELECT f.*, u.name as "user_name" 
 FROM feeds f
-join users u on f.user_id = u.id
+left join users u on f.user_id = u.id
 ORDER BY f.created_at DESC;
 
+-- name: GetFeedByID :one
+SELECT f.*
+FROM feeds f
+WHERE f.id = $1;
+
 -- name: GetFeedByUrl :one
 SELECT f.*
 FROM feeds f
 WHERE f.url = $1
 LIMIT 1;
 
+-- name: GetFeedByCanonicalUrl :one
+SELECT f.*
+FROM feeds f
+WHERE f.canonical_url = $1;
+
 -- name: MarkFeedFetched :exec
 UPDATE feeds
 SET last_fetched_at = $1
 WHERE id = $2;
 
+-- name: SetFeedRetiredAt :exec
+UPDATE feeds
+SET retired_at = $1, next_fetch_at = NULL
+WHERE id = $2;
+
+-- name: SetFeedParseRecovered :exec
+UPDATE feeds
+SET parse_recovered = $1
+WHERE id = $2;
+
+-- name: SetFeedNextFetchAt :exec
+UPDATE feeds
+SET next_fetch_at = $1
+WHERE id = $2;
+
 -- name: GetNextFeedToFetch :one
 SELECT *
 FROM feeds
+WHERE retired_at IS NULL
+AND (next_fetch_at IS NULL OR next_fetch_at <= sqlc.arg(now)::timestamp)
 ORDER BY last_fetched_at NULLS FIRST, created_at ASC
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: UpdateFeedUrl :exec
+UPDATE feeds
+SET url = $1, canonical_url = $2, updated_at = $3
+WHERE id = $4;
+
+-- name: RenameFeed :exec
+UPDATE feeds
+SET name = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: SetFeedCanonicalUrl :exec
+UPDATE feeds
+SET canonical_url = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: ListFeedsForOwner :many
+SELECT *
+FROM feeds
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: SetFeedOwner :exec
+UPDATE feeds
+SET user_id = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: MarkOrphanedFeeds :execrows
+UPDATE feeds f
+SET orphaned_at = sqlc.arg(now)::timestamp
+WHERE f.orphaned_at IS NULL
+AND NOT EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id);
+
+-- name: ClearAdoptedFeeds :execrows
+UPDATE feeds f
+SET orphaned_at = NULL
+WHERE f.orphaned_at IS NOT NULL
+AND EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id);
+
+-- name: ListOrphanedFeeds :many
+SELECT f.*
+FROM feeds f
+WHERE f.orphaned_at <= sqlc.arg(orphaned_before)::timestamp
+AND NOT EXISTS (SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id)
+AND NOT EXISTS (
+    SELECT 1 FROM posts p
+    JOIN post_keeps k ON k.post_id = p.id
+    WHERE p.feed_id = f.id
+)
+ORDER BY f.orphaned_at;
@@ -1,5 +1,5 @@
 -- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content, published_at_estimated, image_url)
 VALUES (
     $1,
     $2,
@@ -8,7 +8,10 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11
 )
 RETURNING *;
 
@@ -17,6 +20,104 @@ SELECT p.*, f.name as "feed_name"
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
 JOIN feed_follows ff ON f.id = ff.feed_id
-WHERE ff.user_id = $1
-ORDER BY p.published_at DESC
-LIMIT $2;
\ No newline at end of file
+WHERE ff.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(author)::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors pa
+    WHERE pa.post_id = p.id AND lower(pa.name) = lower(sqlc.narg(author)::text)
+))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt
+    WHERE pt.post_id = p.id AND lower(pt.tag) = lower(sqlc.narg(tag)::text)
+))
+ORDER BY p.published_at DESC, p.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SearchPostsForUser :many
+SELECT p.*, f.name as "feed_name"
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+AND (p.title ILIKE '%' || sqlc.arg(query)::text || '%' ESCAPE '\'
+    OR p.description ILIKE '%' || sqlc.arg(query)::text || '%' ESCAPE '\'
+    OR p.content ILIKE '%' || sqlc.arg(query)::text || '%' ESCAPE '\')
+AND (sqlc.narg(author)::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors pa
+    WHERE pa.post_id = p.id AND lower(pa.name) = lower(sqlc.narg(author)::text)
+))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags pt
+    WHERE pt.post_id = p.id AND lower(pt.tag) = lower(sqlc.narg(tag)::text)
+))
+ORDER BY p.published_at DESC, p.id
+LIMIT sqlc.arg('limit');
+
+-- name: GetPostByID :one
+SELECT *
+FROM posts
+WHERE id = $1;
+
+-- name: GetPostByUrl :one
+SELECT *
+FROM posts
+WHERE url = $1;
+
+-- name: AddPostTag :exec
+INSERT INTO post_tags (post_id, tag)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (post_id, tag) DO NOTHING;
+
+-- name: AddPostAuthor :exec
+INSERT INTO post_authors (post_id, name)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (post_id, name) DO NOTHING;
+
+-- name: ListAuthorsForPost :many
+SELECT name
+FROM post_authors
+WHERE post_id = $1
+ORDER BY name;
+
+-- name: ListTagsForPost :many
+SELECT tag
+FROM post_tags
+WHERE post_id = $1
+ORDER BY tag;
+
+-- name: SetPostImagePath :exec
+UPDATE posts
+SET image_path = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListPostImagePaths :many
+SELECT image_path
+FROM posts
+WHERE image_path IS NOT NULL;
+
+-- name: CountFeedPosts :one
+SELECT COUNT(*)
+FROM posts
+WHERE feed_id = $1;
+
+-- name: MoveFeedPosts :execrows
+UPDATE posts
+SET feed_id = sqlc.arg(keeper_id)
+WHERE feed_id = sqlc.arg(duplicate_id);
+
+-- name: CountFeedPostsSince :one
+SELECT COUNT(*)
+FROM posts
+WHERE feed_id = $1 AND published_at >= $2;
+
+-- name: GetNewestPostForFeed :one
+SELECT *
+FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC NULLS LAST
+LIMIT 1;
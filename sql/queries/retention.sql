@@ -0,0 +1,104 @@
+-- name: UpsertFeedRetention :one
+INSERT INTO feed_retention (feed_id, created_at, updated_at, max_posts, max_age_seconds)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    max_posts = EXCLUDED.max_posts,
+    max_age_seconds = EXCLUDED.max_age_seconds
+RETURNING *;
+
+-- name: GetFeedRetention :one
+SELECT *
+FROM feed_retention
+WHERE feed_id = $1;
+
+-- name: DeleteFeedRetention :exec
+DELETE FROM feed_retention
+WHERE feed_id = $1;
+
+-- name: ListFeedRetentionPolicies :many
+SELECT f.id, f.name, f.url, r.max_posts, r.max_age_seconds
+FROM feeds f
+LEFT JOIN feed_retention r ON f.id = r.feed_id
+ORDER BY f.name;
+
+-- name: MoveFeedRetention :execrows
+UPDATE feed_retention
+SET feed_id = sqlc.arg(keeper_id)
+WHERE feed_id = sqlc.arg(duplicate_id)
+AND NOT EXISTS (
+    SELECT 1 FROM feed_retention WHERE feed_id = sqlc.arg(keeper_id)
+);
+
+-- name: CountPrunablePosts :one
+SELECT COUNT(*)
+FROM posts p
+WHERE p.feed_id = sqlc.arg(feed_id)
+AND NOT EXISTS (
+    SELECT 1 FROM post_keeps k WHERE k.post_id = p.id
+)
+AND (
+    (sqlc.narg(published_before)::timestamp IS NOT NULL
+        AND COALESCE(p.published_at, p.created_at) < sqlc.narg(published_before)::timestamp)
+    OR (sqlc.arg(keep_latest)::int > 0 AND p.id NOT IN (
+        SELECT latest.id
+        FROM posts latest
+        WHERE latest.feed_id = sqlc.arg(feed_id)
+        ORDER BY latest.published_at DESC NULLS LAST, latest.created_at DESC
+        LIMIT sqlc.arg(keep_latest)::int
+    ))
+);
+
+-- name: PrunePosts :execrows
+WITH pruned AS (
+    DELETE FROM posts p
+    WHERE p.feed_id = sqlc.arg(feed_id)
+    AND NOT EXISTS (
+        SELECT 1 FROM post_keeps k WHERE k.post_id = p.id
+    )
+    AND (
+        (sqlc.narg(published_before)::timestamp IS NOT NULL
+            AND COALESCE(p.published_at, p.created_at) < sqlc.narg(published_before)::timestamp)
+        OR (sqlc.arg(keep_latest)::int > 0 AND p.id NOT IN (
+            SELECT latest.id
+            FROM posts latest
+            WHERE latest.feed_id = sqlc.arg(feed_id)
+            ORDER BY latest.published_at DESC NULLS LAST, latest.created_at DESC
+            LIMIT sqlc.arg(keep_latest)::int
+        ))
+    )
+    RETURNING p.url, p.feed_id
+)
+INSERT INTO pruned_posts (url, feed_id, pruned_at)
+SELECT url, feed_id, NOW()
+FROM pruned
+ON CONFLICT (url) DO UPDATE SET pruned_at = EXCLUDED.pruned_at;
+
+-- name: IsPostPruned :one
+SELECT EXISTS (
+    SELECT 1 FROM pruned_posts WHERE url = $1
+);
+
+-- name: TrimPrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE feed_id = sqlc.arg(feed_id)
+AND url <> ALL(sqlc.arg(listed_urls)::text[]);
+
+-- name: KeepPost :exec
+INSERT INTO post_keeps (user_id, post_id, created_at)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnkeepPost :execrows
+DELETE FROM post_keeps
+WHERE user_id = $1 AND post_id = $2;
\ No newline at end of file
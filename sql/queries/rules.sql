@@ -0,0 +1,30 @@
+-- name: CreateRule :one
+INSERT INTO rules (id, created_at, updated_at, user_id, feed_id, action, field, match_type, pattern)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING *;
+
+-- name: ListRulesForUser :many
+SELECT r.*, f.url AS feed_url
+FROM rules r
+LEFT JOIN feeds f ON r.feed_id = f.id
+WHERE r.user_id = $1
+ORDER BY r.created_at;
+
+-- name: DeleteRule :execrows
+DELETE FROM rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: MoveFeedRules :execrows
+UPDATE rules
+SET feed_id = sqlc.arg(keeper_id), updated_at = NOW()
+WHERE feed_id = sqlc.arg(duplicate_id);
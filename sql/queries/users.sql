@@ -18,4 +18,8 @@ WHERE name = $1;
 
 -- name: ListUsers :many
 SELECT * FROM users
-ORDER BY name ASC;
\ No newline at end of file
+ORDER BY name ASC;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
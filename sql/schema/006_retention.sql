@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE feed_retention (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    max_posts INTEGER,
+    max_age_seconds BIGINT
+);
+
+CREATE TABLE post_keeps (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE post_keeps;
+DROP TABLE feed_retention;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE rules (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('mute', 'highlight')),
+    field TEXT NOT NULL CHECK (field IN ('title', 'description', 'any')),
+    match_type TEXT NOT NULL CHECK (match_type IN ('contains', 'regex')),
+    pattern TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE rules;
\ No newline at end of file
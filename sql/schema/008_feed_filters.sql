@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE feed_filters (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('drop', 'strip-tracking', 'rewrite', 'tag')),
+    pattern TEXT NOT NULL DEFAULT '',
+    replacement TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE post_tags (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (post_id, tag)
+);
+
+-- +goose Down
+DROP TABLE post_tags;
+DROP TABLE feed_filters;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table posts
+    add column content TEXT;
+
+-- +goose Down
+alter table posts
+    drop column content;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table feeds
+    add column parse_recovered BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+alter table feeds
+    drop column parse_recovered;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table posts
+    add column published_at_estimated BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+alter table posts
+    drop column published_at_estimated;
\ No newline at end of file
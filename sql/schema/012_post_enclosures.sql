@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE post_enclosures (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    url VARCHAR(2000) NOT NULL,
+    mime_type TEXT NOT NULL DEFAULT '',
+    length_bytes BIGINT,
+    duration_seconds INTEGER,
+    UNIQUE (post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE post_episodes (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    duration_seconds INTEGER,
+    episode INTEGER,
+    season INTEGER,
+    image_url TEXT,
+    transcript_url TEXT,
+    transcript_type TEXT,
+    chapters_url TEXT
+);
+
+-- +goose Down
+DROP TABLE post_episodes;
\ No newline at end of file
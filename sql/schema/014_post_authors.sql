@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE post_authors (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    PRIMARY KEY (post_id, name)
+);
+
+CREATE INDEX post_authors_name_idx ON post_authors (lower(name));
+CREATE INDEX post_tags_tag_idx ON post_tags (lower(tag));
+
+-- +goose Down
+DROP INDEX post_tags_tag_idx;
+DROP TABLE post_authors;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN image_url VARCHAR(2000);
+ALTER TABLE posts ADD COLUMN image_path TEXT;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN image_path;
+ALTER TABLE posts DROP COLUMN image_url;
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table feeds
+    add column next_fetch_at TIMESTAMP;
+
+-- +goose Down
+alter table feeds
+    drop column next_fetch_at;
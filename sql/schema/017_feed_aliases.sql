@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE feed_aliases (
+    url VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX feed_aliases_feed_id_idx ON feed_aliases (feed_id);
+
+-- +goose Down
+DROP TABLE feed_aliases;
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table feeds
+    add column retired_at TIMESTAMP;
+
+-- +goose Down
+alter table feeds
+    drop column retired_at;
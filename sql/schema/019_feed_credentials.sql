@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE feed_credentials (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('basic', 'bearer', 'query', 'header')),
+    name TEXT NOT NULL DEFAULT '',
+    secret BYTEA NOT NULL,
+    UNIQUE (feed_id, kind, name)
+);
+
+-- +goose Down
+DROP TABLE feed_credentials;
@@ -0,0 +1,127 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN canonical_url VARCHAR(255);
+
+-- Approximates rss.CanonicalURL: drop the scheme, credentials, "www.", the
+-- default port, the fragment and trailing slashes, and lowercase the host.
+-- Run `gator feeds dedupe` afterwards to apply the exact rules, such as
+-- query parameter ordering.
+UPDATE feeds f
+SET canonical_url = c.canonical_url
+FROM (
+    SELECT id,
+        regexp_replace(regexp_replace(lower(regexp_replace(authority, '^.*@', '')), ':(80|443)$', ''), '^www\.', '')
+            || rtrim(split_part(remainder, '?', 1), '/')
+            || coalesce(nullif(substring(remainder FROM '\?.*$'), '?'), '') AS canonical_url
+    FROM (
+        SELECT id,
+            substring(rest FROM '^[^/?]*') AS authority,
+            substring(rest FROM '^[^/?]*(.*)$') AS remainder
+        FROM (
+            SELECT id, regexp_replace(split_part(url, '#', 1), '^[A-Za-z][A-Za-z0-9+.-]*://', '') AS rest
+            FROM feeds
+        ) stripped
+    ) split
+) c
+WHERE f.id = c.id;
+
+-- Merge duplicates into the oldest feed with the same canonical URL.
+CREATE TEMPORARY TABLE feed_merges AS
+SELECT f.id AS duplicate_id, f.url AS duplicate_url, k.id AS keeper_id
+FROM feeds f
+JOIN LATERAL (
+    SELECT id
+    FROM feeds k
+    WHERE k.canonical_url = f.canonical_url
+    ORDER BY k.created_at, k.id
+    LIMIT 1
+) k ON k.id <> f.id;
+
+-- Move each user's earliest follow of a duplicate unless they already
+-- follow the keeper. Remaining follows are removed with the duplicate.
+UPDATE feed_follows ff
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE ff.feed_id = m.duplicate_id
+AND ff.id = (
+    SELECT ff2.id
+    FROM feed_follows ff2
+    JOIN feed_merges m2 ON ff2.feed_id = m2.duplicate_id
+    WHERE m2.keeper_id = m.keeper_id AND ff2.user_id = ff.user_id
+    ORDER BY ff2.created_at, ff2.id
+    LIMIT 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM feed_follows k
+    WHERE k.feed_id = m.keeper_id AND k.user_id = ff.user_id
+);
+
+UPDATE posts p
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE p.feed_id = m.duplicate_id;
+
+UPDATE rules r
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE r.feed_id = m.duplicate_id;
+
+UPDATE feed_filters ff
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE ff.feed_id = m.duplicate_id;
+
+-- Credentials and retention overrides move unless the keeper has its own.
+UPDATE feed_credentials c
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE c.feed_id = m.duplicate_id
+AND c.id = (
+    SELECT c2.id
+    FROM feed_credentials c2
+    JOIN feed_merges m2 ON c2.feed_id = m2.duplicate_id
+    WHERE m2.keeper_id = m.keeper_id AND c2.kind = c.kind AND c2.name = c.name
+    ORDER BY c2.updated_at DESC, c2.id
+    LIMIT 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM feed_credentials k
+    WHERE k.feed_id = m.keeper_id AND k.kind = c.kind AND k.name = c.name
+);
+
+UPDATE feed_retention r
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE r.feed_id = m.duplicate_id
+AND r.feed_id = (
+    SELECT r2.feed_id
+    FROM feed_retention r2
+    JOIN feed_merges m2 ON r2.feed_id = m2.duplicate_id
+    WHERE m2.keeper_id = m.keeper_id
+    ORDER BY r2.updated_at DESC, r2.feed_id
+    LIMIT 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM feed_retention k WHERE k.feed_id = m.keeper_id
+);
+
+UPDATE feed_aliases a
+SET feed_id = m.keeper_id
+FROM feed_merges m
+WHERE a.feed_id = m.duplicate_id;
+
+INSERT INTO feed_aliases (url, created_at, feed_id)
+SELECT duplicate_url, NOW(), keeper_id
+FROM feed_merges
+ON CONFLICT (url) DO NOTHING;
+
+DELETE FROM feeds
+WHERE id IN (SELECT duplicate_id FROM feed_merges);
+
+DROP TABLE feed_merges;
+
+ALTER TABLE feeds ALTER COLUMN canonical_url SET NOT NULL;
+ALTER TABLE feeds ADD CONSTRAINT feeds_canonical_url_key UNIQUE (canonical_url);
+
+-- +goose Down
+-- Merged feeds are not restored.
+ALTER TABLE feeds DROP COLUMN canonical_url;
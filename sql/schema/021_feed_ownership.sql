@@ -0,0 +1,21 @@
+-- +goose Up
+-- Feeds outlive their creator: ownership is transferred by `users delete`,
+-- and a feed whose owner is deleted any other way has no owner.
+ALTER TABLE feeds ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE feeds DROP CONSTRAINT feeds_user_id_fkey;
+ALTER TABLE feeds ADD CONSTRAINT feeds_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+alter table feeds
+    add column orphaned_at TIMESTAMP;
+
+-- +goose Down
+alter table feeds
+    drop column orphaned_at;
+
+-- Feeds without an owner cannot be kept once user_id is required again.
+DELETE FROM feeds WHERE user_id IS NULL;
+ALTER TABLE feeds DROP CONSTRAINT feeds_user_id_fkey;
+ALTER TABLE feeds ADD CONSTRAINT feeds_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE feeds ALTER COLUMN user_id SET NOT NULL;
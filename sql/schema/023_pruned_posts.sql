@@ -0,0 +1,10 @@
+-- +goose Up
+-- URLs of pruned posts, so that items still in a feed are not stored again.
+CREATE TABLE pruned_posts (
+    url VARCHAR(1000) PRIMARY KEY,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    pruned_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE pruned_posts;